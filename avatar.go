@@ -0,0 +1,95 @@
+package svgpattern
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/kpym/svgpattern/template/tempfunc"
+)
+
+// An avatar is a pattern clipped in a fixed size shape.
+type avatar struct {
+	size  float64
+	shape string
+}
+
+// avatarShapes are the available avatar shapes.
+var avatarShapes = []string{"square", "circle", "rounded"}
+
+// avatarTemplate wraps the pattern svg (.Pattern) in a fixed size avatar.
+var avatarTemplate = template.Must(template.New("avatar").Parse(`<svg width="{{ .Size }}" height="{{ .Size }}" viewBox="0 0 {{ .Size }} {{ .Size }}" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">
+  <defs>
+    <clipPath id="{{ .ID }}avatar-clip">
+    {{- if eq .Shape "circle" }}
+      <circle cx="{{ .Center }}" cy="{{ .Center }}" r="{{ .Center }}"/>
+    {{- else if eq .Shape "rounded" }}
+      <rect width="{{ .Size }}" height="{{ .Size }}" rx="{{ .Radius }}"/>
+    {{- else }}
+      <rect width="{{ .Size }}" height="{{ .Size }}"/>
+    {{- end }}
+    </clipPath>
+  </defs>
+  <g clip-path="url(#{{ .ID }}avatar-clip)">
+  {{ .Pattern }}
+  </g>
+  {{- if .Initials }}
+  <text x="{{ .Center }}" y="{{ .Center }}" fill="{{ .Fill }}" font-family="sans-serif" font-size="{{ .FontSize }}" text-anchor="middle" dominant-baseline="central">{{ html .Initials }}</text>
+  {{- end }}
+</svg>
+`))
+
+// avatarSVG wraps the pattern svg in the avatar shape.
+func (g *generator) avatarSVG(pattern []byte) ([]byte, error) {
+	var result bytes.Buffer
+
+	data := struct {
+		Size, Center, Radius, FontSize float64
+		Shape, Initials, Fill, Pattern string
+		ID                             string
+	}{
+		g.avatar.size,
+		g.avatar.size / 2,
+		g.avatar.size / 8,
+		g.avatar.size * 0.4,
+		g.avatar.shape,
+		g.initials,
+		tempfunc.Contrast(g.color),
+		string(bytes.TrimSpace(pattern)),
+		g.idPrefix,
+	}
+	err := avatarTemplate.Execute(&result, data)
+
+	return result.Bytes(), err
+}
+
+// WithAvatar is a Generator option that clips the pattern in a fixed size (in pixels)
+// shape like an avatar. The available shapes are 'square', 'circle' and 'rounded' (square).
+// If the shape is unknown a square is used.
+func WithAvatar(size float64, shape string) Option {
+	return func(g *generator) {
+		if size <= 0 {
+			g.addError(fmt.Sprintf("Invalid avatar size %v.", size))
+			return
+		}
+		valid := false
+		for _, s := range avatarShapes {
+			valid = valid || s == shape
+		}
+		if !valid {
+			g.addError(fmt.Sprintf("Unknown avatar shape %s, use square.", shape))
+			shape = "square"
+		}
+
+		g.avatar = &avatar{size, shape}
+	}
+}
+
+// WithInitials is a Generator option that writes the initials in the center of the avatar.
+// The text color (black or white) is chosen for contrast against the background color.
+// This option has effect only with WithAvatar.
+func WithInitials(initials string) Option {
+	return func(g *generator) {
+		g.initials = initials
+	}
+}
@@ -0,0 +1,57 @@
+package svgpattern
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithAvatar(t *testing.T) {
+	data := []struct {
+		shape string
+		clip  string
+	}{
+		{"circle", `<circle cx="64" cy="64" r="64"/>`},
+		{"square", `<rect width="128" height="128"/>`},
+		{"rounded", `<rect width="128" height="128" rx="16"/>`},
+	}
+	for _, tt := range data {
+		g := New("Test", WithAvatar(128, tt.shape))
+		svg, ok := g.Generate()
+		if !ok {
+			t.Error("There are errors in the generator.", g.Errors())
+		}
+		s := string(svg)
+		if !strings.HasPrefix(s, `<svg width="128" height="128" viewBox="0 0 128 128"`) {
+			t.Errorf("The avatar should have fixed size, got: %s", s[:80])
+		}
+		if !strings.Contains(s, tt.clip) {
+			t.Errorf("The %s avatar should be clipped with %s.", tt.shape, tt.clip)
+		}
+		if strings.Contains(s, "<text") {
+			t.Errorf("The avatar should not contain text without initials.")
+		}
+	}
+
+	g := New("Test", WithAvatar(128, "star"))
+	if len(g.Errors()) != 1 {
+		t.Error("There should be an error (unknown shape).", g.Errors())
+	}
+	g = New("Test", WithAvatar(-1, "circle"))
+	if len(g.Errors()) != 1 {
+		t.Error("There should be an error (negative size).", g.Errors())
+	}
+}
+
+func TestWithInitials(t *testing.T) {
+	g := New("Test", WithColor("#fff"), WithAvatar(100, "circle"), WithInitials("K&P"))
+	svg, _ := g.Generate()
+	if !strings.Contains(string(svg), `fill="#000000" font-family="sans-serif" font-size="40" text-anchor="middle" dominant-baseline="central">K&amp;P</text>`) {
+		t.Errorf("The escaped initials should be black on white background, got: %s", svg)
+	}
+
+	g = New("Test", WithInitials("KP"))
+	svg, _ = g.Generate()
+	if strings.Contains(string(svg), "KP") {
+		t.Errorf("The initials should be used only for avatars.")
+	}
+}
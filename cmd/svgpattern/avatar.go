@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kpym/svgpattern"
+	flag "github.com/spf13/pflag"
+)
+
+// avatar is the 'avatar' command that prints a fixed size avatar
+// made from the pattern of the phrase.
+func avatar(args []string) {
+	var (
+		p        parameters
+		size     float64
+		shape    string
+		initials string
+	)
+	fs := flag.NewFlagSet("avatar", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: svgpattern avatar 'phrase' [parameters].\nThe available parameters are:\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Float64Var(&size, "size", 128, "The avatar size in pixels.")
+	fs.StringVar(&shape, "shape", "circle", "The avatar shape: 'circle', 'square' or 'rounded'.")
+	fs.StringVar(&initials, "initials", "", "The initials to write in the center of the avatar.")
+	p.declare(fs, true)
+	fs.Parse(args)
+	phrase := checkPhrase(fs)
+
+	options := append(p.versionOptions(), p.modelOptions()...)
+	options = append(options, p.patternOptions()...)
+	options = append(options, svgpattern.WithAvatar(size, shape))
+	if initials != "" {
+		options = append(options, svgpattern.WithInitials(initials))
+	}
+	g := svgpattern.New(phrase, options...)
+	svg, ok := g.Generate()
+	if !ok {
+		log("There are some errors : %v", g.Errors())
+	}
+	os.Stdout.Write(svg)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+
+	"github.com/kpym/svgpattern"
+	flag "github.com/spf13/pflag"
+)
+
+// A tile is a single pattern of an html page (gallery or sheet).
+type tile struct {
+	Title   string
+	Comment string
+	Color   string
+	Command string
+	Image   template.URL
+	Errors  []string
+}
+
+// newTile generates the pattern and save it as tile.
+func newTile(title string, g svgpattern.Generator) tile {
+	t := tile{Title: title, Color: g.Color()}
+	svg, ok := g.Generate()
+	t.Image = template.URL("data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(svg))
+	if !ok {
+		t.Errors = g.Errors()
+	}
+
+	return t
+}
+
+// page is the html template of the gallery and sheet commands.
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+  body { font-family: sans-serif; margin: 1em; background: #f8f8f8; color: #222; }
+  main { display: grid; grid-template-columns: repeat(auto-fill, minmax({{ .Width }}px, 1fr)); gap: 1em; }
+  figure { margin: 0; background: #fff; border: 1px solid #ddd; }
+  img { display: block; width: 100%; height: {{ .Height }}px; object-fit: cover; }
+  figcaption { padding: 0.5em; font-size: 0.8em; }
+  figcaption h2 { margin: 0 0 0.3em 0; font-size: 1.2em; }
+  .swatch { display: inline-block; width: 0.8em; height: 0.8em; border: 1px solid #888; vertical-align: middle; }
+  .error { color: #a00; }
+  code { display: block; margin-top: 0.3em; word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<main>
+{{- range .Tiles }}
+<figure>
+  <img src="{{ .Image }}" alt="{{ .Title }}">
+  <figcaption>
+    <h2>{{ .Title }}</h2>
+    {{- if .Comment }}
+    <div>{{ .Comment }}</div>
+    {{- end }}
+    <div><span class="swatch" style="background: {{ .Color }}"></span> {{ .Color }}</div>
+    {{- range .Errors }}
+    <div class="error">{{ . }}</div>
+    {{- end }}
+    {{- if .Command }}
+    <code>{{ .Command }}</code>
+    {{- end }}
+  </figcaption>
+</figure>
+{{- end }}
+</main>
+</body>
+</html>
+`))
+
+// writePage writes the html page to output, or to stdout if output is empty.
+func writePage(output, title string, width, height int, tiles []tile) {
+	var result bytes.Buffer
+	err := page.Execute(&result, struct {
+		Title         string
+		Width, Height int
+		Tiles         []tile
+	}{title, width, height, tiles})
+	if err != nil {
+		log("Error generating the html page: %v\n", err)
+		os.Exit(1)
+	}
+
+	if output == "" {
+		os.Stdout.Write(result.Bytes())
+		return
+	}
+	err = os.WriteFile(output, result.Bytes(), 0644)
+	if err != nil {
+		log("Error writing the file '%s': %v\n", output, err)
+		os.Exit(1)
+	}
+}
+
+// quote the string s for the shell.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// command provides the svgpattern command line that reproduces a pattern
+// from the phrase, the flags set in fs (except the skipped ones) and the extra arguments.
+// A phrase equal to a command name (or like a flag) is the last argument, after '--'.
+func command(phrase string, fs *flag.FlagSet, skip []string, extra ...string) string {
+	last := strings.HasPrefix(phrase, "-")
+	for _, name := range []string{"models", "gallery", "sheet", "avatar"} {
+		last = last || phrase == name
+	}
+	args := []string{"svgpattern"}
+	if !last {
+		args = append(args, quote(phrase))
+	}
+	args = append(args, extra...)
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range skip {
+			if f.Name == s {
+				return
+			}
+		}
+		values := []string{f.Value.String()}
+		if sv, ok := f.Value.(flag.SliceValue); ok {
+			values = sv.GetSlice()
+		}
+		for _, v := range values {
+			args = append(args, "--"+f.Name+"="+quote(v))
+		}
+	})
+	if last {
+		args = append(args, "--", quote(phrase))
+	}
+
+	return strings.Join(args, " ")
+}
+
+// gallery is the 'gallery' command that renders all the models
+// for a phrase in a single html page.
+func gallery(args []string) {
+	var (
+		p      parameters
+		output string
+	)
+	fs := flag.NewFlagSet("gallery", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: svgpattern gallery 'phrase' [parameters].\nThe available parameters are:\n\n")
+		fs.PrintDefaults()
+	}
+	fs.StringVarP(&output, "output", "o", "", "The html output file. If not provided the stdout is used.")
+	p.declare(fs, true)
+	fs.Parse(args)
+	phrase := checkPhrase(fs)
+
+	options := p.patternOptions()
+	models := p.selectedModels()
+	if len(models) == 0 {
+		log("No models to render.\n")
+		os.Exit(1)
+	}
+	tiles := make([]tile, len(models))
+	for i, model := range models {
+		m := model.Meta()
+		g := svgpattern.New(phrase, append(append(p.versionOptions(), svgpattern.WithModel(m.Name)), options...)...)
+		tiles[i] = newTile(m.Name, g)
+		tiles[i].Comment = m.Description
+		tiles[i].Command = command(phrase, fs, []string{"output", "model", "tag", "exclude"}, "--model="+m.Name)
+	}
+
+	writePage(output, "svgpattern gallery for "+phrase, 300, 200, tiles)
+}
@@ -20,14 +20,35 @@ var version = "dev"
 // onlycolor is a flag to only output the color
 var onlycolor bool
 
+// fragment is a flag to only output the <defs> fragment
+var fragment bool
+
+// fragmentID is the id of the pattern in the <defs> fragment
+var fragmentID string
+
 // Aide affiche l'aide d'utilisation
 func help() {
 	var out = os.Stderr
 	fmt.Fprintf(out, "svgpattern (version: %s)\n\n", version)
-	fmt.Fprintf(out, "Usage: svgpattern 'phrase' [parapeters].\nThe available parameters are:\n\n")
+	fmt.Fprintf(out, "Usage: svgpattern 'phrase' [parapeters].\n       svgpattern models [--long].\n       svgpattern gallery 'phrase' [-o file.html] [parapeters].\n       svgpattern avatar 'phrase' [--size 128] [--shape circle|square|rounded] [--initials AB] [parapeters].\n       svgpattern sheet ['phrase'] [-n count] [-o file.html] [parapeters].\nA phrase equal to a command name (models, gallery, sheet or avatar) should follow '--', like: svgpattern -- models.\nThe available parameters are:\n\n")
 	flag.PrintDefaults()
 	fmt.Fprintf(out, "\n")
-	fmt.Fprintf(out, "The available pattern models are: %s\n\n", model.EmbeddedModels.ModelsString())
+	fmt.Fprintf(out, "The available pattern models are:\n\n%s\n", model.EmbeddedModels.ModelsDescription(false))
+	fmt.Fprintf(out, "Use 'svgpattern models --long' for more details on the models.\n\n")
+}
+
+// listModels is the 'models' command that prints the available models.
+func listModels(args []string) {
+	var long bool
+	fs := flag.NewFlagSet("models", flag.ExitOnError)
+	fs.BoolVarP(&long, "long", "l", false, "Print all the model metadata.")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: svgpattern models [parameters].\nThe available parameters are:\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	fmt.Print(model.EmbeddedModels.ModelsDescription(long))
 }
 
 // parseValues provide mean value and deviation from string
@@ -82,7 +103,7 @@ func parseValues(s string) (v, d float64, ok bool) {
 }
 
 // getOptions provide set/randomize options based on the strval paramater
-// this function is to transform the values of the flags : u, a, l, r, s
+// this function is to transform the values of the flags : u, a, l, o, r, s
 func getOptions(strval string, with, rand func(float64) svgpattern.Option) (o []svgpattern.Option, ok bool) {
 	val, dev, ok := parseValues(strval)
 	if !ok {
@@ -97,77 +118,173 @@ func getOptions(strval string, with, rand func(float64) svgpattern.Option) (o []
 	return
 }
 
-// generatorFromParameters provides a new Generator using the CLI parameters.
-func generatorFromParameters() svgpattern.Generator {
-	// The parameter global variables
-	var (
-		color      string
-		hue        string
-		saturation string
-		lightness  string
-		model      string
-		rotate     string
-		scale      string
-	)
+// splitList splits a comma separated list and trim the elements.
+func splitList(s string) []string {
+	list := strings.Split(s, ",")
+	for i, e := range list {
+		list[i] = strings.TrimSpace(e)
+	}
+
+	return list
+}
+
+// modelOption provides the model selection option from the model flag value.
+// "a,b" → WithModel("a", "b")
+// "a:3,b" → WithModelWeights({"a": 3, "b": 1})
+func modelOption(value string) (o svgpattern.Option, ok bool) {
+	set := strings.Split(value, ",")
+	weights := make(map[string]float64, len(set))
+	weighted := false
+	for i, m := range set {
+		name, weight, found := strings.Cut(m, ":")
+		set[i] = strings.TrimSpace(name)
+		weights[set[i]] = 1
+		if found {
+			w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+			if err != nil {
+				return nil, false
+			}
+			weights[set[i]] = w
+			weighted = true
+		}
+	}
+	if weighted {
+		return svgpattern.WithModelWeights(weights), true
+	}
+
+	return svgpattern.WithModel(set...), true
+}
 
+// parameters are the values of the flags common to all commands.
+type parameters struct {
+	color      string
+	hue        string
+	saturation string
+	lightness  string
+	opacity    string
+	model      string
+	tag        string
+	exclude    string
+	rotate     string
+	scale      string
+	params     []string
+	version    string
+	palette    string
+	idPrefix   string
+	text       string
+	font       string
+	textSize   float64
+	textAnchor string
+}
+
+// declare the flags of the parameters in the flag set fs.
+// If withModels is false the model selection flags are not declared.
+func (p *parameters) declare(fs *flag.FlagSet, withModels bool) {
 	// preserve the declaration order of the flags
-	flag.Usage = help
-	flag.CommandLine.SortFlags = false
-	// declare the flags
-	flag.StringVarP(&model, "model", "m", "", "The pattern model. If multiple choices separate by comma.")
-	flag.StringVarP(&color, "color", "c", "", "The background color in hex, like '#a17', or 'no' for transparent background.")
-	flag.StringVarP(&hue, "hue", "u", "", "The hue variation in degree (using HSL colors). Value format is '[value][~deviation]' or 'min:max'.")
-	flag.StringVarP(&saturation, "saturation", "a", "", "The saturation variation (using HSL colors). Value format is '[value][~deviation]' or 'min:max'.")
-	flag.StringVarP(&lightness, "lightness", "l", "", "The lightness variation (using HSL colors). Value format is '[value][~deviation]' or 'min:max'.")
-	flag.StringVarP(&rotate, "rotate", "r", "", "Rotation angle in degree. Value format is '[value][~deviation]' or 'min:max'.")
-	flag.StringVarP(&scale, "scale", "s", "", "Scale factor. Value format is '[value][~deviation]' or 'min:max'.")
-	flag.BoolVar(&onlycolor, "onlycolor", false, "Only output the color.")
-	//parse the flags
-	flag.Parse()
-	// chack if parameters were provided
-	if len(os.Args) == 1 {
-		help()
-		os.Exit(1)
+	fs.SortFlags = false
+	if withModels {
+		fs.StringVarP(&p.model, "model", "m", "", "The pattern model. If multiple choices separate by comma. A weight can be added like 'hexagons:3,plaid:1'.")
+		fs.StringVarP(&p.tag, "tag", "t", "", "Use only the models having all these tags, like 'geometric', 'organic', 'busy' or 'subtle'. If multiple tags separate by comma.")
+		fs.StringVarP(&p.exclude, "exclude", "x", "", "Exclude these models. If multiple models separate by comma.")
 	}
-	// check the positional parameters
-	if flag.NArg() != 1 {
-		log("Exactly one positional parameter is expexted.\n")
-		if flag.NArg() == 0 {
-			log("No positional parameters were provided.\n")
-		} else {
-			log("Provided %d positional parameters: '%s'.\n", flag.NArg(), strings.Join(flag.Args(), "', '"))
-		}
-		os.Exit(1)
+	fs.StringVarP(&p.color, "color", "c", "", "The background color in hex, like '#a17', or 'no' for transparent background.")
+	fs.StringVarP(&p.hue, "hue", "u", "", "The hue variation in degree (using HSL colors). Value format is '[value][~deviation]' or 'min:max'.")
+	fs.StringVarP(&p.saturation, "saturation", "a", "", "The saturation variation (using HSL colors). Value format is '[value][~deviation]' or 'min:max'.")
+	fs.StringVarP(&p.lightness, "lightness", "l", "", "The lightness variation (using HSL colors). Value format is '[value][~deviation]' or 'min:max'.")
+	fs.StringVar(&p.opacity, "opacity", "", "The background opacity in [0,1]. Value format is '[value][~deviation]' or 'min:max'.")
+	fs.StringVarP(&p.rotate, "rotate", "r", "", "Rotation angle in degree. Value format is '[value][~deviation]' or 'min:max'.")
+	fs.StringVarP(&p.scale, "scale", "s", "", "Scale factor. Value format is '[value][~deviation]' or 'min:max'.")
+	fs.StringArrayVarP(&p.params, "param", "p", nil, "A model parameter as 'key=value' (see 'svgpattern models --long'). Can be repeated.")
+	fs.StringVar(&p.palette, "palette", "", "The dark and light colors of the pattern elements separated by comma, like '#222,#ddd'.")
+	fs.StringVar(&p.idPrefix, "id-prefix", "", "The prefix of the svg element ids, or 'auto' for a phrase derived one. Useful to inline several patterns in the same html page.")
+	fs.StringVar(&p.text, "text", "", "A text to write on top of the pattern.")
+	fs.StringVar(&p.font, "font", "sans-serif", "The font family of the text.")
+	fs.Float64Var(&p.textSize, "text-size", 48, "The font size of the text in pixels.")
+	fs.StringVar(&p.textAnchor, "text-anchor", "center", "The text position: 'center', 'top', 'bottom', 'left', 'right', 'top-left', 'top-right', 'bottom-left' or 'bottom-right'.")
+	fs.StringVar(&p.version, "output-version", "", fmt.Sprintf("The output version, one of: %s. The latest one is used by default.", strings.Join(model.VersionNames, ", ")))
+}
+
+// versionOptions provides the output version option from the flags.
+// This option should be the first one.
+func (p *parameters) versionOptions() (options []svgpattern.Option) {
+	if p.version != "" {
+		options = append(options, svgpattern.WithOutputVersion(p.version))
 	}
-	// seed the generator
-	g := svgpattern.New(flag.Arg(0))
+
+	return options
+}
+
+// modelOptions provides the model selection options from the flags.
+// In case of parsing error the program exits.
+func (p *parameters) modelOptions() (options []svgpattern.Option) {
 	// set the model
-	if model != "" {
-		set := strings.Split(model, ",")
-		for i, m := range set {
-			set[i] = strings.TrimSpace(m)
+	if p.model != "" {
+		o, ok := modelOption(p.model)
+		if !ok {
+			log("Error parsing the model parameter '%s'.\n", p.model)
+			os.Exit(1)
+		}
+		options = append(options, o)
+	}
+	// filter the models
+	if p.tag != "" {
+		options = append(options, svgpattern.WithTags(splitList(p.tag)...))
+	}
+	if p.exclude != "" {
+		options = append(options, svgpattern.WithoutModels(splitList(p.exclude)...))
+	}
+
+	return options
+}
+
+// selectedModels provides the embedded models selected by the model selection flags.
+// The model weights are ignored.
+func (p *parameters) selectedModels() model.Models {
+	models := model.EmbeddedModels
+	if p.version != "" {
+		models = model.Versions[p.version]
+	}
+	if p.model != "" {
+		names := splitList(p.model)
+		for i, name := range names {
+			names[i], _, _ = strings.Cut(name, ":")
+			names[i] = strings.TrimSpace(names[i])
 		}
-		g.Options(svgpattern.WithModel(set...))
+		models, _ = models.SelectModels(names...)
+	}
+	if p.tag != "" {
+		models = models.Filter(splitList(p.tag)...)
 	}
+	if p.exclude != "" {
+		models = models.Exclude(splitList(p.exclude)...)
+	}
+
+	return models
+}
+
+// patternOptions provides the color, transformation and model parameters options from the flags.
+// In case of parsing error the program exits.
+func (p *parameters) patternOptions() (options []svgpattern.Option) {
 	// set the color + opacity
-	color = strings.TrimSpace(color)
+	color := strings.TrimSpace(p.color)
 	if color != "" {
 		if color == "no" {
-			g.Options(svgpattern.WithoutColor())
+			options = append(options, svgpattern.WithoutColor())
 		} else {
-			g.Options(svgpattern.WithColor(color))
+			options = append(options, svgpattern.WithColor(color))
 		}
 	}
-	// set/randomize the hue, saturation, lightness, rotate and scale
+	// set/randomize the hue, saturation, lightness, opacity, rotate and scale
 	for _, par := range []struct {
 		name, value string
 		with, rand  func(float64) svgpattern.Option
 	}{
-		{"hue", hue, svgpattern.WithHue, svgpattern.RandomizeHue},
-		{"saturation", saturation, svgpattern.WithSaturation, svgpattern.RandomizeSaturation},
-		{"lightness", lightness, svgpattern.WithLightness, svgpattern.RandomizeLightness},
-		{"rotate", rotate, svgpattern.WithRotation, svgpattern.RandomizeRotation},
-		{"scale", scale, svgpattern.WithScale, svgpattern.RandomizeScale},
+		{"hue", p.hue, svgpattern.WithHue, svgpattern.RandomizeHue},
+		{"saturation", p.saturation, svgpattern.WithSaturation, svgpattern.RandomizeSaturation},
+		{"lightness", p.lightness, svgpattern.WithLightness, svgpattern.RandomizeLightness},
+		{"opacity", p.opacity, svgpattern.WithOpacity, svgpattern.RandomizeOpacity},
+		{"rotate", p.rotate, svgpattern.WithRotation, svgpattern.RandomizeRotation},
+		{"scale", p.scale, svgpattern.WithScale, svgpattern.RandomizeScale},
 	} {
 		if par.value != "" {
 			o, ok := getOptions(par.value, par.with, par.rand)
@@ -175,11 +292,79 @@ func generatorFromParameters() svgpattern.Generator {
 				log("Error parsing the %s parameter '%s'.\n", par.name, par.value)
 				os.Exit(1)
 			}
-			g.Options(o...)
+			options = append(options, o...)
+		}
+	}
+	// set the model parameters
+	for _, kv := range p.params {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			log("Error parsing the param parameter '%s', the format is 'key=value'.\n", kv)
+			os.Exit(1)
 		}
+		options = append(options, svgpattern.WithParam(strings.TrimSpace(key), strings.TrimSpace(value)))
+	}
+	// set the palette
+	if p.palette != "" {
+		colors := splitList(p.palette)
+		if len(colors) != 2 {
+			log("Error parsing the palette parameter '%s', the format is 'dark,light'.\n", p.palette)
+			os.Exit(1)
+		}
+		options = append(options, svgpattern.WithPalette(colors[0], colors[1]))
+	}
+	// set the id prefix
+	if p.idPrefix == "auto" {
+		options = append(options, svgpattern.WithIDPrefix(""))
+	} else if p.idPrefix != "" {
+		options = append(options, svgpattern.WithIDPrefix(p.idPrefix))
+	}
+	// set the text
+	if p.text != "" {
+		options = append(options, svgpattern.WithText(p.text, p.font, p.textSize, p.textAnchor))
 	}
 
-	return g
+	return options
+}
+
+// checkPhrase verifies that exactly one positional parameter (the phrase) is provided.
+// If this is not the case, the program exits.
+func checkPhrase(fs *flag.FlagSet) string {
+	if fs.NArg() != 1 {
+		log("Exactly one positional parameter is expexted.\n")
+		if fs.NArg() == 0 {
+			log("No positional parameters were provided.\n")
+		} else {
+			log("Provided %d positional parameters: '%s'.\n", fs.NArg(), strings.Join(fs.Args(), "', '"))
+		}
+		os.Exit(1)
+	}
+
+	return fs.Arg(0)
+}
+
+// generatorFromParameters provides a new Generator using the CLI parameters.
+func generatorFromParameters() svgpattern.Generator {
+	var p parameters
+
+	flag.Usage = help
+	// declare the flags
+	p.declare(flag.CommandLine, true)
+	flag.BoolVar(&onlycolor, "onlycolor", false, "Only output the color.")
+	flag.BoolVar(&fragment, "fragment", false, "Only output the <defs> fragment with a <pattern> of id --fragment-id, to be used as fill='url(#id)' in another svg.")
+	flag.StringVar(&fragmentID, "fragment-id", "pattern", "The id of the <pattern> of the <defs> fragment.")
+	//parse the flags
+	flag.Parse()
+	// chack if parameters were provided
+	if len(os.Args) == 1 {
+		help()
+		os.Exit(1)
+	}
+	// check the positional parameters
+	phrase := checkPhrase(flag.CommandLine)
+	// seed the generator
+	options := append(p.versionOptions(), p.modelOptions()...)
+	return svgpattern.New(phrase, append(options, p.patternOptions()...)...)
 }
 
 func log(format string, a ...interface{}) (n int, err error) {
@@ -188,12 +373,37 @@ func log(format string, a ...interface{}) (n int, err error) {
 
 // Prints pattern's SVG string with a specific background color
 func main() {
+	// the command is the first argument, so 'svgpattern -- models' uses 'models' as phrase
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "models":
+			listModels(os.Args[2:])
+			return
+		case "gallery":
+			gallery(os.Args[2:])
+			return
+		case "sheet":
+			sheet(os.Args[2:])
+			return
+		case "avatar":
+			avatar(os.Args[2:])
+			return
+		}
+	}
 	g := generatorFromParameters()
 	if onlycolor {
 		fmt.Println(g.Color())
 		return
 	}
-	svg, ok := g.Generate()
+	var (
+		svg []byte
+		ok  bool
+	)
+	if fragment {
+		svg, ok = g.GenerateDefs(fragmentID)
+	} else {
+		svg, ok = g.Generate()
+	}
 	if !ok {
 		log("There are some errors : %v", g.Errors())
 	}
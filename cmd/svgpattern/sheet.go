@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kpym/svgpattern"
+	flag "github.com/spf13/pflag"
+)
+
+// paramsString provides the sorted list of 'key=value' model parameters.
+func paramsString(params map[string]string) string {
+	list := make([]string, 0, len(params))
+	for k, v := range params {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+
+	return strings.Join(list, ", ")
+}
+
+// sheet is the 'sheet' command that renders the same model (or model selection)
+// for many seeds in a single html page.
+// The phrase of the n-th pattern is 'phrase-n', or simply 'n' if no phrase is provided.
+func sheet(args []string) {
+	var (
+		p      parameters
+		output string
+		count  int
+	)
+	fs := flag.NewFlagSet("sheet", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: svgpattern sheet ['phrase'] [parameters].\nThe available parameters are:\n\n")
+		fs.PrintDefaults()
+	}
+	fs.StringVarP(&output, "output", "o", "", "The html output file. If not provided the stdout is used.")
+	fs.IntVarP(&count, "count", "n", 64, "The number of patterns.")
+	p.declare(fs, true)
+	fs.Parse(args)
+	if count <= 0 {
+		log("The number of patterns should be positive, got %d.\n", count)
+		os.Exit(1)
+	}
+
+	var phrase string
+	if fs.NArg() > 0 {
+		phrase = checkPhrase(fs)
+	}
+
+	options := append(p.versionOptions(), p.modelOptions()...)
+	options = append(options, p.patternOptions()...)
+	tiles := make([]tile, 0, count)
+	for i := 1; i <= count; i++ {
+		cellPhrase := fmt.Sprintf("%s-%d", phrase, i)
+		if phrase == "" {
+			cellPhrase = fmt.Sprint(i)
+		}
+		g := svgpattern.New(cellPhrase, options...)
+		t := newTile(fmt.Sprintf("%s (%s)", cellPhrase, g.Model()), g)
+		t.Comment = fmt.Sprintf("seed: %d", g.Seed())
+		if params := g.Params(); len(params) > 0 {
+			t.Comment += "; " + paramsString(params)
+		}
+		t.Command = command(cellPhrase, fs, []string{"output", "count"})
+		tiles = append(tiles, t)
+	}
+
+	title := "svgpattern sheet"
+	if p.model != "" {
+		title += " for " + p.model
+	}
+	writePage(output, title, 180, 120, tiles)
+}
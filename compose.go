@@ -0,0 +1,118 @@
+package svgpattern
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// A Layer is a pattern of a composition (see Compose).
+type Layer struct {
+	// Model is the model name. If empty, the model of the generator is used.
+	Model string
+	// Rotate is the rotation angle in degree.
+	Rotate float64
+	// Scale is the scale factor. If zero, 1 is used.
+	Scale float64
+	// Opacity is the opacity of the layer in [0,1]. If zero, 1 is used.
+	Opacity float64
+	// Palette is the dark and the light colors of the pattern elements.
+	// If empty or not valid, the palette of the generator is used.
+	Palette [2]string
+}
+
+// composeTemplate stacks the layers (.Layers) on top of the background.
+var composeTemplate = template.Must(template.New("compose").Parse(`<svg width="100%" height="100%" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">
+  {{- if gt .Opacity 0.0 }}
+  <rect fill="{{ .Color }}" height="100%" width="100%" x="0" y="0" {{ if lt .Opacity 1.0 }}fill-opacity="{{ .Opacity }}"{{ end }}/>
+  {{- end }}
+  {{- range .Layers }}
+  <g{{ if lt .Opacity 1.0 }} opacity="{{ .Opacity }}"{{ end }}>
+  {{ .SVG }}
+  </g>
+  {{- end }}
+</svg>
+`))
+
+// compose renders all layers on top of the background.
+// Each layer is rendered without background and with its own id prefix.
+func (g *generator) compose() ([]byte, error) {
+	type renderedLayer struct {
+		Opacity float64
+		SVG     string
+	}
+	var layers []renderedLayer
+
+	all := g.versionModels()
+	for i, l := range g.layers {
+		name := l.Model
+		if name == "" {
+			name = g.name
+		}
+		index, ok := all.GetModelIndex(name)
+		if !ok {
+			g.addError(fmt.Sprintf("Unknown model %s for layer %d.", name, i+1))
+			continue
+		}
+		render, err := g.prepare(all[index], fmt.Sprintf("template-layer%d", i+1))
+		if err != nil {
+			g.addError("Error parsing template " + name + ": " + err.Error())
+			continue
+		}
+
+		data := g.data()
+		data.ID = fmt.Sprintf("%slayer%d-", g.idPrefix, i+1)
+		data.Opacity = 0
+		data.Rotate = l.Rotate
+		data.Scale = l.Scale
+		if data.Scale == 0 {
+			data.Scale = 1
+		}
+		dark, light := l.Palette[0], l.Palette[1]
+		if dark == "" {
+			dark = data.Dark
+		}
+		if light == "" {
+			light = data.Light
+		}
+		if validPalette(dark, light) {
+			data.Dark, data.Light = dark, light
+		} else {
+			g.addError(fmt.Sprintf("Error parsing palette of layer %d: %s, %s.", i+1, dark, light))
+		}
+		svg, err := g.execute(name, render, data)
+		if err != nil {
+			return nil, err
+		}
+
+		opacity := l.Opacity
+		if opacity == 0 {
+			opacity = 1
+		}
+		layers = append(layers, renderedLayer{opacity, string(bytes.TrimSpace(svg))})
+	}
+
+	var result bytes.Buffer
+	err := composeTemplate.Execute(&result, struct {
+		Color   string
+		Opacity float64
+		Layers  []renderedLayer
+	}{g.color.Hex(), g.opacity, layers})
+
+	return result.Bytes(), err
+}
+
+// Compose is a Generator option that stacks several patterns (layers)
+// on top of the background, each one with its own model, rotation, scale,
+// opacity and palette. The rotation and the scale of the generator are ignored.
+// Without layers the generator produces a single pattern.
+// The v1 models do not namespace their ids, so the composition needs v2 or later.
+func Compose(layers ...Layer) Option {
+	return func(g *generator) {
+		if !g.output.namespaced {
+			g.addError(fmt.Sprintf("The %s models do not support compositions.", g.version))
+			return
+		}
+		g.layers = layers
+	}
+}
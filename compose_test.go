@@ -0,0 +1,76 @@
+package svgpattern
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	g := New("Test", WithColor("#123456"), Compose(
+		Layer{Model: "squares"},
+		Layer{Model: "sin-waves", Rotate: 45, Scale: 2, Opacity: 0.5, Palette: [2]string{"#000", "#fff"}},
+	))
+	svg, ok := g.Generate()
+	if !ok {
+		t.Error("There are errors in the generator.", g.Errors())
+	}
+	s := string(svg)
+	for _, want := range []string{
+		`<rect fill="#123456" height="100%" width="100%" x="0" y="0" />`,
+		`id="layer1-pattern"`,
+		`fill="url(#layer1-pattern)"`,
+		`<g opacity="0.5">`,
+		`id="layer2-pattern" patternTransform="rotate(45) scale(2)"`,
+		`href="#layer2-wave1"`,
+		`stroke="#fff"`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("The composition should contain %s, got: %s", want, s)
+		}
+	}
+	if strings.Count(s, `<rect fill="#123456"`) != 1 {
+		t.Errorf("The composition should have a single background, got: %s", s)
+	}
+	if strings.Contains(s, `id="pattern"`) {
+		t.Errorf("The layer ids should be namespaced, got: %s", s)
+	}
+}
+
+func TestComposeUnknownModel(t *testing.T) {
+	g := New("Test", WithModel("plaid"), Compose(Layer{Model: "bingo"}, Layer{}))
+	svg, ok := g.Generate()
+	if ok || len(g.Errors()) != 1 {
+		t.Error("There should be an error (unknown model).", g.Errors())
+	}
+	if !strings.Contains(string(svg), `id="layer2-pattern"`) {
+		t.Errorf("The layer without model should use the generator model, got: %s", svg)
+	}
+}
+
+func TestComposeV1(t *testing.T) {
+	g := New("Test", WithOutputVersion("v1"), Compose(Layer{Model: "hexagons"}, Layer{Model: "plaid"}))
+	svg, ok := g.Generate()
+	if ok || len(g.Errors()) != 1 || strings.Count(string(svg), `id="pattern"`) != 1 {
+		t.Errorf("The v1 compositions should produce an error and a single pattern, got errors %v.", g.Errors())
+	}
+}
+
+func TestWithPalette(t *testing.T) {
+	g := New("Test", WithModel("squares"), WithPalette("#a00", "#0a0"))
+	svg, _ := g.Generate()
+	s := string(svg)
+	if strings.Contains(s, "#222") || strings.Contains(s, "#ddd") || !strings.Contains(s, `fill="#a00"`) {
+		t.Errorf("The palette should replace the default colors, got: %s", s)
+	}
+
+	g = New("Test", WithModel("squares"), WithPalette(`"/><script>`, "#0a0"))
+	svg, ok := g.Generate()
+	if ok || len(g.Errors()) != 1 || strings.Contains(string(svg), "<script>") || !strings.Contains(string(svg), "#222") {
+		t.Errorf("An invalid palette should produce an error and keep the default colors, got errors %v.", g.Errors())
+	}
+	g = New("Test", Compose(Layer{Model: "squares", Palette: [2]string{"#000", `"/><script>`}}))
+	svg, ok = g.Generate()
+	if ok || len(g.Errors()) != 1 || strings.Contains(string(svg), "<script>") {
+		t.Errorf("An invalid layer palette should produce an error, got errors %v.", g.Errors())
+	}
+}
@@ -0,0 +1,53 @@
+package svgpattern
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// GenerateDefs provides only the <defs> part of the svg pattern,
+// to be inserted in another svg document. The id of the <pattern> element is
+// the provided one, and the ids of the other elements are prefixed by "id-".
+// The background color, the avatar and the text are not part of the result,
+// and the compositions (see Compose) are not supported.
+// The pattern can then be used as fill="url(#id)".
+// The second parameter is true if no errors are present.
+func (g *generator) GenerateDefs(id string) (defs []byte, ok bool) {
+	if !validID.MatchString(id) {
+		g.addError(fmt.Sprintf("Invalid pattern id '%s'.", id))
+		return nil, false
+	}
+	if len(g.layers) > 0 {
+		g.addError("The fragments of compositions are not supported.")
+		return nil, false
+	}
+	if g.name == "" || g.render == nil {
+		g.addError("Missing template.")
+		return nil, false
+	}
+
+	g.resolved = make(map[string]string)
+	data := g.data()
+	data.ID = id + "-"
+	svg, err := g.execute(g.name, g.render, data)
+	if err != nil {
+		g.addError(err.Error())
+		return nil, false
+	}
+
+	start := bytes.Index(svg, []byte("<defs>"))
+	end := bytes.LastIndex(svg, []byte("</defs>"))
+	if start < 0 || end < start {
+		g.addError("Missing <defs> in the template " + g.name)
+		return nil, false
+	}
+	defs = svg[start : end+len("</defs>")]
+	pattern := []byte(`id="` + id + `-pattern"`)
+	if !bytes.Contains(defs, pattern) {
+		g.addError(fmt.Sprintf("The template %s does not prefix its ids (use the output version v2 or later).", g.name))
+		return nil, false
+	}
+	defs = bytes.Replace(defs, pattern, []byte(`id="`+id+`"`), 1)
+
+	return defs, len(g.errors) == 0
+}
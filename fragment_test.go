@@ -0,0 +1,51 @@
+package svgpattern
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kpym/svgpattern/template/model"
+)
+
+func TestGenerateDefs(t *testing.T) {
+	for _, m := range model.EmbeddedModels {
+		g := New("Test", WithModel(m.Meta().Name))
+		defs, ok := g.GenerateDefs("fill")
+		if !ok {
+			t.Error("There are errors in the generator.", g.Errors())
+		}
+		s := string(defs)
+		if !strings.HasPrefix(s, "<defs>") || !strings.HasSuffix(s, "</defs>") {
+			t.Errorf("The fragment of %s should be a <defs> element, got: %s", m.Meta().Name, s)
+		}
+		if strings.Count(s, `id="fill"`) != 1 || !strings.Contains(s, `<pattern  id="fill"`) {
+			t.Errorf("The pattern of %s should have the provided id, got: %s", m.Meta().Name, s)
+		}
+		if strings.Contains(s, "url(#") || strings.Contains(s, "<svg") {
+			t.Errorf("The fragment of %s should not contain the background, got: %s", m.Meta().Name, s)
+		}
+	}
+
+	for _, name := range []string{"hexagons", "voronoi-cells", "maze"} {
+		g := New("Test", WithModel(name), WithIDPrefix("fill-"))
+		svg, _ := g.Generate()
+		defs, _ := g.GenerateDefs("fill")
+		s := strings.Replace(string(defs), `id="fill"`, `id="fill-pattern"`, 1)
+		if !strings.Contains(string(svg), s) {
+			t.Errorf("The fragment of %s should be the defs of the generated svg, got %s and %s", name, defs, svg)
+		}
+	}
+
+	g := New("Test")
+	if _, ok := g.GenerateDefs("1 bad"); ok || len(g.Errors()) != 1 {
+		t.Error("There should be an error (invalid id).", g.Errors())
+	}
+	g = New("Test", WithOutputVersion("v1"))
+	if _, ok := g.GenerateDefs("fill"); ok || len(g.Errors()) != 1 {
+		t.Error("There should be an error (v1 model without id prefix).", g.Errors())
+	}
+	g = New("Test", Compose(Layer{}))
+	if _, ok := g.GenerateDefs("fill"); ok || len(g.Errors()) != 1 {
+		t.Error("There should be an error (composition).", g.Errors())
+	}
+}
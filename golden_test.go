@@ -0,0 +1,88 @@
+package svgpattern
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kpym/svgpattern/template/model"
+)
+
+// update the golden files with: go test -run Golden -update
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// goldenPhrases are the phrases used to generate the golden files.
+var goldenPhrases = []string{"Test", "kpym"}
+
+// goldenOptions are the sets of options used to generate the golden files.
+var goldenOptions = []struct {
+	name    string
+	options []Option
+}{
+	{"default", nil},
+	{"color", []Option{WithColor("#a17"), RandomizeHue(30), WithOpacity(0.5)}},
+	{"transform", []Option{WithoutColor(), WithRotation(30), WithScaleBetween(0.5, 1.5)}},
+}
+
+// checkGolden compares the svg with the golden file (or update it).
+func checkGolden(t *testing.T, file string, svg []byte) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, svg, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Errorf("Can't read the golden file %s (use -update to create it): %v", file, err)
+		return
+	}
+	if !bytes.Equal(svg, want) {
+		t.Errorf("The generated svg differs from the golden file %s (use -update if this is intended).", file)
+	}
+}
+
+// The golden files of a released version should never be updated.
+func TestGoldenModels(t *testing.T) {
+	for _, version := range model.VersionNames {
+		for _, m := range model.Versions[version] {
+			for _, phrase := range goldenPhrases {
+				for _, set := range goldenOptions {
+					options := append([]Option{WithOutputVersion(version), WithModel(m.Meta().Name)}, set.options...)
+					g := New(phrase, options...)
+					svg, ok := g.Generate()
+					if !ok {
+						t.Errorf("Errors generating %s %s for '%s' with %s options: %v", version, m.Meta().Name, phrase, set.name, g.Errors())
+						continue
+					}
+					file := filepath.Join("testdata", "golden", version, m.Meta().Name, phrase+"-"+set.name+".svg")
+					checkGolden(t, file, svg)
+				}
+			}
+		}
+	}
+}
+
+// The default model choice of each phrase should never change for a released version.
+// The v1 files are the output of the first release (before the output versions).
+func TestGoldenDefaultModel(t *testing.T) {
+	for _, version := range model.VersionNames {
+		for _, phrase := range append(goldenPhrases, "alice") {
+			g := New(phrase, WithOutputVersion(version))
+			svg, ok := g.Generate()
+			if !ok {
+				t.Errorf("Errors generating %s for '%s': %v", version, phrase, g.Errors())
+				continue
+			}
+			file := filepath.Join("testdata", "golden", version, "_default", phrase+".svg")
+			checkGolden(t, file, svg)
+		}
+	}
+}
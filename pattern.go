@@ -9,9 +9,13 @@ package svgpattern
 import (
 	"bytes"
 	"crypto/sha1"
+	"encoding/binary"
 	"fmt"
 	"math"
 	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -27,16 +31,26 @@ import (
 // # Random generator
 //
 // A phrase is converted to sha1 hash, from which an int64 is constructed.
-// this integer is used to seed a random generator.
+// Since v2, this integer is used to derive independent random streams, one per parameter
+// (color, model, rotate, scale, template ...). In this way the value of a parameter
+// do not depend on the presence or the order of the options acting on the other ones.
+// In v1 all parameters are drawn, in order, from a single random stream.
 //
 // # Template model
 //
-// # The template models are go-templates available in the module package
+// The models are go-templates or go renderers available in the model package.
+//
+// # Output version
+//
+// The models and the seeding logic are frozen for each output version,
+// so that the same phrase always produce the same pattern with a given version.
 //
 // # Template parameters
 //
 // These parameters are (transformed and) passed to the template to generate
 // the pattern. They are randomly selected if not determined by an option.
+// The model specific parameters (set by WithParam) are available as .Params
+// and through the `param` template function.
 //
 // # Errors
 //
@@ -44,19 +58,39 @@ import (
 // Only messages are saved in the errors field.
 // If some error occurs, in place to stop the process, some random value is used.
 type generator struct {
+	// output version
+	version string
+	output  outputVersion
 	// Random generator
-	phrase string
-	seed   int64
-	rand   *rand.Rand
+	phrase  string
+	seed    int64
+	streams map[string]*rand.Rand
 	// template model
-	models model.Models
-	name   string
-	code   *template.Template
+	models  model.Models
+	weights map[string]float64
+	name    string
+	render  renderFunc
 	// template parameters
 	color   colorful.Color
 	opacity float64
 	rotate  float64
 	scale   float64
+	params  map[string]string
+	dark    string
+	light   string
+	// prefix of the svg element ids
+	idPrefix string
+	// layers of a composition (see Compose)
+	layers []Layer
+	// avatar layout (nil for full page pattern)
+	avatar   *avatar
+	initials string
+	// text on top of the pattern (nil for no text)
+	text *text
+	// parameter values used by the last generation
+	resolved map[string]string
+	// number of options applied (see WithOutputVersion)
+	applied int
 	// status
 	errors []string
 }
@@ -67,8 +101,12 @@ type generator struct {
 type Generator interface {
 	Options(...Option)
 	Generate() (svg []byte, ok bool)
+	GenerateDefs(id string) (defs []byte, ok bool)
 	Errors() []string
 	Color() string
+	Model() string
+	Seed() int64
+	Params() map[string]string
 }
 
 // Errors provide the error messages generated during the initialization/generation process.
@@ -76,35 +114,71 @@ func (g *generator) Errors() []string {
 	return g.errors
 }
 
+// data provides the model data from the generator parameters.
+func (g *generator) data() model.Data {
+	return model.Data{
+		Color:   g.color.Hex(),
+		Opacity: g.opacity,
+		Rotate:  g.rotate,
+		Scale:   g.scale,
+		Params:  g.params,
+		ID:      g.idPrefix,
+		Dark:    g.dark,
+		Light:   g.light,
+		Param:   g.param,
+	}
+}
+
+// A renderFunc renders a model with the data.
+type renderFunc func(data model.Data) ([]byte, error)
+
+// execute the model render function with the data.
+func (g *generator) execute(name string, render renderFunc, data model.Data) ([]byte, error) {
+	svg, err := render(data)
+	if err != nil {
+		g.addError("Error executing the template " + name)
+		return nil, err
+	}
+
+	return svg, nil
+}
+
 // Generate provides the svg pattern as first parameter.
 // The second parameter is true if no errors are present.
 func (g *generator) Generate() (svg []byte, ok bool) {
-	var result bytes.Buffer
-
-	data := struct {
-		Color   string
-		Opacity float64
-		Rotate  float64
-		Scale   float64
-	}{
-		g.color.Hex(),
-		g.opacity,
-		g.rotate,
-		g.scale,
-	}
-
-	if g.name == "" || g.code == nil {
-		g.addError("Missing template.")
-		return nil, false
+	var err error
+
+	g.resolved = make(map[string]string)
+	if len(g.layers) > 0 {
+		svg, err = g.compose()
+	} else {
+		if g.name == "" || g.render == nil {
+			g.addError("Missing template.")
+			return nil, false
+		}
+		svg, err = g.execute(g.name, g.render, g.data())
 	}
-	err := g.code.Execute(&result, data)
 	if err != nil {
-		g.addError("Error executing the template " + g.name)
 		g.addError(err.Error())
 		return nil, false
 	}
 
-	return result.Bytes(), len(g.errors) == 0
+	if g.avatar != nil {
+		svg, err = g.avatarSVG(svg)
+		if err != nil {
+			g.addError("Error generating the avatar: " + err.Error())
+			return nil, false
+		}
+	}
+	if g.text != nil {
+		svg, err = g.addText(svg)
+		if err != nil {
+			g.addError("Error adding the text: " + err.Error())
+			return nil, false
+		}
+	}
+
+	return svg, len(g.errors) == 0
 }
 
 // Color returns the color used to generate the pattern as hex string.
@@ -112,6 +186,22 @@ func (g *generator) Color() string {
 	return g.color.Hex()
 }
 
+// Model returns the name of the model used to generate the pattern.
+func (g *generator) Model() string {
+	return g.name
+}
+
+// Seed returns the seed from which all random values are derived.
+func (g *generator) Seed() int64 {
+	return g.seed
+}
+
+// Params returns the model parameters used by the last Generate call,
+// including the default values of the parameters not set by WithParam.
+func (g *generator) Params() map[string]string {
+	return g.resolved
+}
+
 // An Option is a function that customize the Generator.
 type Option func(*generator)
 
@@ -119,6 +209,7 @@ type Option func(*generator)
 func (g *generator) Options(options ...Option) {
 	for _, opt := range options {
 		opt(g)
+		g.applied++
 	}
 }
 
@@ -126,11 +217,14 @@ func (g *generator) Options(options ...Option) {
 func New(phrase string, options ...Option) Generator {
 	// create the pattern generator
 	g := new(generator)
-	// set the models to default
-	g.models = model.EmbeddedModels
+	// set the output version and the models to default
+	g.version = model.LatestVersion
+	g.output = outputVersions[g.version]
+	g.models = model.Versions[g.version]
 	// init the pattern generator
 	g.phraseSeed(phrase)
 	g.scale = 1
+	g.dark, g.light = defaultDark, defaultLight
 	g.randomColor()
 	g.randomModel()
 	// apply the provided options
@@ -149,9 +243,47 @@ func (g *generator) addError(msg string) {
 }
 
 // setSeed seed the random generator.
+// All previously created random streams are discarded.
 func (g *generator) setSeed(seed int64) {
 	g.seed = seed
-	g.rand = rand.New(rand.NewSource(seed))
+	g.streams = make(map[string]*rand.Rand)
+}
+
+// streamSeed derives the seed of the random stream named label
+// from the generator seed.
+func (g *generator) streamSeed(label string) int64 {
+	return g.output.streamSeed(g.seed, label)
+}
+
+// stream provides the random stream dedicated to the label.
+// Successive calls with the same label continue the same sequence.
+// If the output version has a shared stream, it is provided for all labels.
+func (g *generator) stream(label string) *rand.Rand {
+	if g.output.shared {
+		label = ""
+	}
+	r, ok := g.streams[label]
+	if !ok {
+		seed := g.seed
+		if label != "" {
+			seed = g.streamSeed(label)
+		}
+		r = rand.New(rand.NewSource(seed))
+		g.streams[label] = r
+	}
+
+	return r
+}
+
+// draw provides a new random stream named label, for a selection
+// that should not depend on the previous ones (like the model or the color).
+// If the output version has a shared stream, it is continued.
+func (g *generator) draw(label string) *rand.Rand {
+	if g.output.shared {
+		return g.stream(label)
+	}
+
+	return rand.New(rand.NewSource(g.streamSeed(label)))
 }
 
 // timeSeed is used if no phrase is provided.
@@ -160,7 +292,7 @@ func (g *generator) timeSeed() {
 	g.setSeed(time.Now().UTC().UnixNano())
 }
 
-// phraseSeed use the sha1 sum of the provided phrase to seed the generator.
+// phraseSeed use the provided phrase to seed the generator.
 // In this way the produced 'random' svg pattern will be reproducible.
 func (g *generator) phraseSeed(phrase string) {
 	if phrase == "" {
@@ -169,20 +301,12 @@ func (g *generator) phraseSeed(phrase string) {
 	}
 
 	g.phrase = phrase
-	h := sha1.New()
-	h.Write([]byte(g.phrase))
-	hs := h.Sum(nil)
-	// convert the first 8 bytes to single int64
-	// this gives us eighteen quintillion four hundred forty-six quadrillion seven hundred forty-four trillion
-	// seventy-three billion seven hundred nine million five hundred fifty-one thousand six hundred sixteen possibilities
-	var seed int64
-	for i := 0; i < 8; i++ {
-		seed += int64(hs[0]) << (i * 8)
-	}
-	g.setSeed(seed)
+	g.setSeed(g.output.phraseSeed(phrase))
 }
 
 // randomModel pick a random model from all available models.
+// If weights are provided, the probability to pick a model is proportional to its weight,
+// else all models are equiprobable.
 func (g *generator) randomModel() {
 	numModels := len(g.models)
 	if numModels == 0 {
@@ -190,18 +314,99 @@ func (g *generator) randomModel() {
 		return
 	}
 
-	index := g.rand.Intn(numModels)
-	m := g.models[index]
-	rf := tempfunc.RandomFunctions(g.seed)
-	uf := tempfunc.UtilFunctions()
-	code, err := template.New(m.Name).Funcs(rf).Funcs(uf).Parse(m.Code)
+	index := g.weightedIndex(g.draw("model"))
+	m := g.models[index].Meta()
+	render, err := g.prepare(g.models[index], "template")
 	if err != nil {
 		g.addError("Error parsing template " + m.Name + ": " + err.Error())
 		return
 	}
 
 	g.name = m.Name
-	g.code = code
+	g.render = render
+}
+
+// prepare provides the render function of the model.
+// The random functions of the template models, and the random generator
+// of the go models, use the random stream named label.
+// This stream is reseeded at each render, so that all renders draw the same pattern.
+func (g *generator) prepare(m model.Model, label string) (renderFunc, error) {
+	switch m := m.(type) {
+	case model.TemplateModel:
+		rf := tempfunc.RandomFunctions(g.streamSeed(label))
+		uf := tempfunc.UtilFunctions()
+		cf := tempfunc.ColorFunctions()
+		pf := template.FuncMap{"param": g.param}
+		code, err := template.New(m.Name).Funcs(rf).Funcs(uf).Funcs(cf).Funcs(pf).Parse(m.Code)
+		if err != nil {
+			return nil, err
+		}
+		return func(data model.Data) ([]byte, error) {
+			var result bytes.Buffer
+			err := code.Funcs(tempfunc.RandomFunctions(g.streamSeed(label))).Execute(&result, data)
+			return result.Bytes(), err
+		}, nil
+	case model.GoModel:
+		return func(data model.Data) ([]byte, error) {
+			var result bytes.Buffer
+			r := rand.New(rand.NewSource(g.streamSeed(label)))
+			err := m.Render(&result, r, data)
+			return result.Bytes(), err
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown kind of model %T", m)
+}
+
+// weightedIndex draws the index of a model from r with respect to the model weights.
+// Without weights all models are equiprobable.
+func (g *generator) weightedIndex(r *rand.Rand) int {
+	numModels := len(g.models)
+	if g.weights == nil {
+		return r.Intn(numModels)
+	}
+
+	total := 0.0
+	for _, m := range g.models {
+		total += g.weights[m.Meta().Name]
+	}
+	x := r.Float64()
+	if total == 0 {
+		return int(x * float64(numModels))
+	}
+	x *= total
+	for i, m := range g.models {
+		x -= g.weights[m.Meta().Name]
+		if x < 0 {
+			return i
+		}
+	}
+
+	return numModels - 1
+}
+
+// selectModels restricts the available models to the 'valid' names.
+// If no valid name is provided all builtin models are used.
+func (g *generator) selectModels(names ...string) {
+	var invalid []string
+	g.models, invalid = g.models.SelectModels(names...)
+	if len(invalid) > 0 {
+		g.addError(fmt.Sprintf("The following %d models are invalid: %s.", len(invalid), strings.Join(invalid, ", ")))
+	}
+	g.checkModels()
+}
+
+// versionModels provides all the models of the output version.
+func (g *generator) versionModels() model.Models {
+	return model.Versions[g.version]
+}
+
+// checkModels replaces an empty set of models by all builtin models.
+func (g *generator) checkModels() {
+	if len(g.models) == 0 {
+		g.addError("Empty set of models. Use all builtin models.")
+		g.models = g.versionModels()
+	}
 }
 
 // WithModel is a Generator option that select the model
@@ -211,19 +416,157 @@ func (g *generator) randomModel() {
 // chosen among all models.
 func WithModel(models ...string) Option {
 	return func(g *generator) {
-		var invalid []string
-		g.models, invalid = g.models.SelectModels(models...)
-		if len(invalid) > 0 {
-			g.addError(fmt.Sprintf("The following %d models are invalid: %s.", len(invalid), strings.Join(invalid, ", ")))
-		}
+		g.selectModels(models...)
+		g.weights = nil
+		g.randomModel()
+	}
+}
 
-		if len(g.models) == 0 {
-			g.addError("Empty set of models. Use all builtin models.")
-			g.models = model.EmbeddedModels
+// WithModelWeights is a Generator option that select the models
+// from the keys of the weights map, in the same way as WithModel.
+// The probability to pick a model is proportional to its weight.
+// Models with negative (or not a number) weight are ignored.
+// If all weights are zero the models are equiprobable.
+func WithModelWeights(weights map[string]float64) Option {
+	return func(g *generator) {
+		names := make([]string, 0, len(weights))
+		for name, w := range weights {
+			if w >= 0 {
+				names = append(names, name)
+			} else {
+				g.addError(fmt.Sprintf("Invalid weight %v for model %s.", w, name))
+			}
+		}
+		// the map order is random, sort the names to be deterministic
+		sort.Strings(names)
+		g.selectModels(names...)
+
+		g.weights = make(map[string]float64, len(names))
+		total := 0.0
+		for _, m := range g.models {
+			name := m.Meta().Name
+			g.weights[name] = weights[name]
+			total += weights[name]
+		}
+		if total == 0 {
+			g.weights = nil
 		}
 
 		g.randomModel()
-		return
+	}
+}
+
+// WithTags is a Generator option that keeps only the models
+// having all the provided tags (like 'geometric' or 'subtle').
+// The model is then randomly chosen among them.
+func WithTags(tags ...string) Option {
+	return func(g *generator) {
+		g.models = g.models.Filter(tags...)
+		g.checkModels()
+		g.randomModel()
+	}
+}
+
+// WithoutModels is a Generator option that excludes the provided models.
+// The model is then randomly chosen among the remaining ones.
+func WithoutModels(names ...string) Option {
+	return func(g *generator) {
+		g.models = g.models.Exclude(names...)
+		g.checkModels()
+		g.randomModel()
+	}
+}
+
+// param is the `param` template function.
+// It provides the value of the model parameter if set by WithParam,
+// else the default value is provided.
+// The value set by WithParam is parsed with the type of the default value (int or float64)
+// and, if the min and max limits are provided, it should be a number in [min, max].
+// If a step is provided after the limits, the value should be min plus a multiple of the step.
+// If the value is not valid, an error is added and the default value is used.
+// Usage : {{ $nx := param "nx" (randi 3 5) 1 20 }} or {{ $n := param "order" 8 8 32 8 }}
+func (g *generator) param(name string, value interface{}, limits ...interface{}) interface{} {
+	if v, ok := g.params[name]; ok {
+		p, err := parseParam(v, value, limits)
+		if err != nil {
+			g.addError(fmt.Sprintf("Invalid value '%s' for the parameter %s: %v, use %v.", v, name, err, value))
+		} else {
+			value = p
+		}
+	}
+	if g.resolved != nil {
+		g.resolved[name] = fmt.Sprint(value)
+	}
+
+	return value
+}
+
+// parseParam parses the parameter value s with the type of the default value.
+// With limits, a default that is not an int is parsed as float64.
+// If the limits [min, max] are provided, the value should be in this interval,
+// and if the step is provided, the value should be min plus a multiple of the step.
+func parseParam(s string, value interface{}, limits []interface{}) (interface{}, error) {
+	var (
+		p interface{}
+		f float64
+	)
+	switch value.(type) {
+	case int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("not an integer")
+		}
+		p, f = n, float64(n)
+	default:
+		// the other defaults (like strings) are numbers only if limits are provided
+		if _, ok := value.(float64); !ok && len(limits) < 2 {
+			return s, nil
+		}
+		x, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil, fmt.Errorf("not a number")
+		}
+		p, f = x, x
+	}
+
+	if len(limits) >= 2 {
+		min, okmin := limitValue(limits[0])
+		max, okmax := limitValue(limits[1])
+		if okmin && okmax && (f < min || f > max) {
+			return nil, fmt.Errorf("not in [%v, %v]", limits[0], limits[1])
+		}
+		if len(limits) == 3 {
+			step, ok := limitValue(limits[2])
+			if ok && step > 0 && math.Mod(f-min, step) != 0 {
+				return nil, fmt.Errorf("not %v plus a multiple of %v", limits[0], limits[2])
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// limitValue converts the limit of a parameter to float64.
+func limitValue(limit interface{}) (float64, bool) {
+	switch l := limit.(type) {
+	case int:
+		return float64(l), true
+	case float64:
+		return l, true
+	}
+
+	return 0, false
+}
+
+// WithParam is a Generator option that set a model specific parameter.
+// The available parameters depend on the model (see the model metadata).
+// The parameters not used by the model are ignored.
+func WithParam(name, value string) Option {
+	return func(g *generator) {
+		if g.params == nil {
+			g.params = make(map[string]string)
+		}
+		g.params[name] = value
 	}
 }
 
@@ -234,14 +577,15 @@ func (g *generator) setColor(color colorful.Color) {
 }
 
 // setOpacity set the background opacity.
-// Actually only 0 and 1 can be provided from the public interface.
+// The opacity is clamped to [0,1].
 func (g *generator) setOpacity(opacity float64) {
-	g.opacity = opacity
+	g.opacity = math.Min(math.Max(opacity, 0), 1)
 }
 
 // randomColor generate a random background color.
 func (g *generator) randomColor() {
-	randCol := colorful.Hsl(360*g.rand.Float64(), 0.3+0.1*g.rand.Float64(), 0.3+0.1*g.rand.Float64())
+	r := g.draw("color")
+	randCol := colorful.Hsl(360*r.Float64(), 0.3+0.1*r.Float64(), 0.3+0.1*r.Float64())
 	g.setColor(randCol)
 }
 
@@ -262,9 +606,10 @@ func WithColor(hex string) Option {
 }
 
 // rd (random deviation) is a utility function
-// that provides a random number in the interval [-|delta|, |delta|].
-func (g *generator) rd(delta float64) float64 {
-	return delta * (1 - 2*g.rand.Float64())
+// that provides a random number in the interval [-|delta|, |delta|]
+// drawn from the random stream named label.
+func (g *generator) rd(label string, delta float64) float64 {
+	return delta * (1 - 2*g.stream(label).Float64())
 }
 
 // WithHue set the color hue of the HSL representation.
@@ -282,7 +627,7 @@ func WithHue(hue float64) Option {
 func RandomizeHue(delta float64) Option {
 	return func(g *generator) {
 		h, s, l := g.color.Hsl()
-		rh := h + g.rd(delta)
+		rh := h + g.rd("hue", delta)
 		// no need to normalize rh, it is defined mod 360.
 		g.color = colorful.Hsl(rh, s, l)
 	}
@@ -303,7 +648,7 @@ func WithSaturation(sat float64) Option {
 func RandomizeSaturation(delta float64) Option {
 	return func(g *generator) {
 		h, s, l := g.color.Hsl()
-		rs := s + g.rd(delta)
+		rs := s + g.rd("saturation", delta)
 		rs = math.Min(math.Max(rs, 0), 1)
 		g.color = colorful.Hsl(h, rs, l)
 	}
@@ -324,7 +669,7 @@ func WithLightness(light float64) Option {
 func RandomizeLightness(delta float64) Option {
 	return func(g *generator) {
 		h, s, l := g.color.Hsl()
-		rl := l + g.rd(delta)
+		rl := l + g.rd("lightness", delta)
 		rl = math.Min(math.Max(rl, 0), 1)
 		g.color = colorful.Hsl(h, s, rl)
 	}
@@ -339,6 +684,97 @@ func WithoutColor() Option {
 	}
 }
 
+// The default palette of the pattern elements.
+const (
+	defaultDark  = "#222"
+	defaultLight = "#ddd"
+)
+
+// WithPalette is a Generator option that set the dark and light colors
+// used by the models to draw the pattern elements (by default "#222" and "#ddd").
+// If a color is not a valid hex color, the palette is not changed.
+// The v1 models ignore the palette, so an error is added.
+func WithPalette(dark, light string) Option {
+	return func(g *generator) {
+		if !g.output.namespaced {
+			g.addError(fmt.Sprintf("The %s models do not support palettes.", g.version))
+			return
+		}
+		if !validPalette(dark, light) {
+			g.addError(fmt.Sprintf("Error parsing palette: %s, %s.", dark, light))
+			return
+		}
+		g.dark, g.light = dark, light
+	}
+}
+
+// validPalette verifies that the dark and light colors are valid hex colors.
+func validPalette(dark, light string) bool {
+	_, errd := colorful.Hex(dark)
+	_, errl := colorful.Hex(light)
+
+	return errd == nil && errl == nil
+}
+
+// validID matches the valid id prefixes.
+var validID = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// WithIDPrefix is a Generator option that prefixes all the svg element ids,
+// like "prefix-pattern" in place of "pattern" for the prefix "prefix-".
+// This allows to have several patterns inline in the same html page.
+// The v1 models ignore the prefix, so an error is added.
+// If the prefix is empty, a prefix derived from the phrase is used,
+// so that different phrases have different prefixes.
+// If the prefix is not valid (it should start with a letter and contain
+// only letters, digits, '_', '.' and '-') the phrase derived prefix is used.
+func WithIDPrefix(prefix string) Option {
+	return func(g *generator) {
+		if !g.output.namespaced {
+			g.addError(fmt.Sprintf("The %s models do not support id prefixes.", g.version))
+			return
+		}
+		if prefix != "" && !validID.MatchString(prefix) {
+			g.addError(fmt.Sprintf("Invalid id prefix '%s', use a phrase derived one.", prefix))
+			prefix = ""
+		}
+		if prefix == "" {
+			prefix = g.phrasePrefix()
+		}
+		g.idPrefix = prefix
+	}
+}
+
+// phrasePrefix provides an id prefix from the first 4 bytes of the sha1 sum
+// of the phrase (or of the seed if there is no phrase).
+func (g *generator) phrasePrefix() string {
+	h := sha1.New()
+	if g.phrase != "" {
+		h.Write([]byte(g.phrase))
+	} else {
+		binary.Write(h, binary.LittleEndian, g.seed)
+	}
+
+	return fmt.Sprintf("p%x-", h.Sum(nil)[:4])
+}
+
+// WithOpacity is a Generator option that set the background opacity.
+// The opacity should be in [0,1], 0 meaning no background and 1 opaque background.
+// This option should be used after WithColor, which resets the opacity to 1.
+func WithOpacity(opacity float64) Option {
+	return func(g *generator) {
+		g.setOpacity(opacity)
+	}
+}
+
+// RandomizeOpacity is a Generator option that randomize the background opacity.
+// The (absolute value of) delta parameter is the maximal deviation of the already provided opacity in [0,1].
+// This option should be used after WithColor.
+func RandomizeOpacity(delta float64) Option {
+	return func(g *generator) {
+		g.setOpacity(g.opacity + g.rd("opacity", delta))
+	}
+}
+
 // WithRotation is a Generator option that fix the pattern rotation transformation.
 func WithRotation(angle float64) Option {
 	return func(g *generator) {
@@ -349,7 +785,7 @@ func WithRotation(angle float64) Option {
 // RandomizeRotation is a Generator option that randomize the rotation angle.
 func RandomizeRotation(delta float64) Option {
 	return func(g *generator) {
-		g.rotate += g.rd(delta)
+		g.rotate += g.rd("rotate", delta)
 	}
 }
 
@@ -359,7 +795,7 @@ func WithRotationBetween(min, max float64) Option {
 	mid := (max + min) / 2
 	delta := (max - min) / 2
 	return func(g *generator) {
-		g.rotate = mid + g.rd(delta)
+		g.rotate = mid + g.rd("rotate", delta)
 	}
 }
 
@@ -373,7 +809,7 @@ func WithScale(factor float64) Option {
 // RandomizeScale is a Generator option that randomize the scale factor.
 func RandomizeScale(delta float64) Option {
 	return func(g *generator) {
-		g.scale += g.rd(delta)
+		g.scale += g.rd("scale", delta)
 	}
 }
 
@@ -383,6 +819,6 @@ func WithScaleBetween(min, max float64) Option {
 	mid := (max + min) / 2
 	delta := (max - min) / 2
 	return func(g *generator) {
-		g.scale = mid + g.rd(delta)
+		g.scale = mid + g.rd("scale", delta)
 	}
 }
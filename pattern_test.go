@@ -1,8 +1,12 @@
 package svgpattern
 
 import (
+	"fmt"
+	"io"
 	"math"
 	"math/rand"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/kpym/svgpattern/template/model"
@@ -15,14 +19,14 @@ func TestDefalutNew(t *testing.T) {
 	if len(g.phrase) > 0 {
 		t.Error("By default phrase shoud be empty.")
 	}
-	if g.rand == nil {
-		t.Error("The random generator should be set.")
+	if g.streams == nil {
+		t.Error("The random streams should be set.")
 	}
 	// template model
 	if g.name == "" {
 		t.Error("The generator template name is empty.")
 	}
-	if g.code == nil {
+	if g.render == nil {
 		t.Error("The generator template is not set.")
 	}
 	// template parameters
@@ -65,17 +69,21 @@ func TestPhraseSeed(t *testing.T) {
 	if string(g.phrase) != "Test" {
 		t.Error("The phrase is not 'Test' but", g.phrase)
 	}
+	if g.seed != -4256609867660129692 {
+		t.Error("The random seed for 'Test' should be -4256609867660129692, but it is", g.seed)
+	}
+	g = New("Test", WithOutputVersion("v1")).(*generator)
 	if g.seed != 7234017283807667300 {
-		t.Error("The random seed for 'Test' should be 7234017283807667300, but it is", g.seed)
+		t.Error("The v1 random seed for 'Test' should be 7234017283807667300, but it is", g.seed)
 	}
-	if g.rand == nil {
-		t.Error("The random generator should be set.")
+	if g.streams == nil {
+		t.Error("The random streams should be set.")
 	}
 	// template model
 	if g.name == "" {
 		t.Error("The generator template name shoud be non empty")
 	}
-	if g.code == nil {
+	if g.render == nil {
 		t.Error("The generator template is not set.")
 	}
 	// template parameters
@@ -101,7 +109,7 @@ func TestPhraseSeed(t *testing.T) {
 }
 
 func TestWithOneModel(t *testing.T) {
-	modelName := model.EmbeddedModels[rand.Intn(len(model.EmbeddedModels))].Name
+	modelName := model.EmbeddedModels[rand.Intn(len(model.EmbeddedModels))].Meta().Name
 	g := New("", WithModel(modelName)).(*generator)
 	// check the name
 	if g.name != modelName {
@@ -113,7 +121,7 @@ func TestWithAllModels(t *testing.T) {
 	g := New("").(*generator)
 	all := make([]string, len(model.EmbeddedModels))
 	for i, m := range model.EmbeddedModels {
-		all[i] = m.Name
+		all[i] = m.Meta().Name
 	}
 	g.Options(WithModel(all...))
 	// status
@@ -278,3 +286,325 @@ func TestWithScaleBetween(t *testing.T) {
 		t.Errorf("The randomized scale is not as desired, we should have %f <= %f <= %f.", min, g.scale, max)
 	}
 }
+
+func TestWithOpacity(t *testing.T) {
+	opacity := 0.3
+	g := New("", WithOpacity(opacity)).(*generator)
+	if g.opacity != opacity {
+		t.Errorf("The opacity is not as desired, want: %f, got: %f.", opacity, g.opacity)
+	}
+	svg, ok := g.Generate()
+	if !ok || !strings.Contains(string(svg), `fill-opacity="0.3"`) {
+		t.Errorf("The background opacity is not present in the svg: %s", svg)
+	}
+
+	g.Options(WithOpacity(1.7))
+	if g.opacity != 1 {
+		t.Errorf("The opacity should be clamped to 1, got: %f.", g.opacity)
+	}
+}
+
+func TestRandomizeOpacity(t *testing.T) {
+	opacity, delta := 0.5, 0.2
+	g := New("", WithOpacity(opacity), RandomizeOpacity(delta)).(*generator)
+	if math.Abs(g.opacity-opacity) > delta {
+		t.Errorf("The randomized opacity is not as desired, we should have |%f - %f| <= %f.", g.opacity, opacity, delta)
+	}
+	// status
+	if len(g.errors) > 0 {
+		t.Error("There are errors in the default generator.", g.errors)
+	}
+}
+
+func TestDistinctSeeds(t *testing.T) {
+	seeds := make(map[int64]bool)
+	for i := 0; i < 1000; i++ {
+		seeds[New(fmt.Sprint("phrase", i)).Seed()] = true
+	}
+	if len(seeds) != 1000 {
+		t.Errorf("1000 phrases should give 1000 seeds, got %d.", len(seeds))
+	}
+}
+
+func TestIndependentStreams(t *testing.T) {
+	g1 := New("Test", WithRotationBetween(-30, 30), WithScaleBetween(1, 2)).(*generator)
+	g2 := New("Test", RandomizeHue(40), WithModel("squares", "plaid"), WithScaleBetween(1, 2), WithRotationBetween(-30, 30)).(*generator)
+	if g1.rotate != g2.rotate {
+		t.Errorf("The rotation should not depend on the other options, got %f and %f.", g1.rotate, g2.rotate)
+	}
+	if g1.scale != g2.scale {
+		t.Errorf("The scale should not depend on the other options, got %f and %f.", g1.scale, g2.scale)
+	}
+
+	g3 := New("Test", WithScaleBetween(1, 2)).(*generator)
+	if g1.color != g3.color || g1.name != g3.name {
+		t.Errorf("The color and the model should not depend on the other options, got (%s, %s) and (%s, %s).", g1.color.Hex(), g1.name, g3.color.Hex(), g3.name)
+	}
+
+	names := []string{"squares", "plaid", "hexagons", "chevrons", "diamonds"}
+	for i := 0; i < 10; i++ {
+		phrase := fmt.Sprint("phrase", i)
+		g4 := New(phrase, WithModel(names...)).(*generator)
+		g5 := New(phrase, WithTags("geometric"), WithoutModels("maze"), WithModel(names...)).(*generator)
+		if g4.name != g5.name {
+			t.Errorf("The model should not depend on the previous selections, got %s and %s.", g4.name, g5.name)
+		}
+	}
+	g5 := New("Test", WithColor("bad")).(*generator)
+	if g5.color != g1.color {
+		t.Errorf("The random color should not depend on the previous draws, got %s and %s.", g5.color.Hex(), g1.color.Hex())
+	}
+}
+
+func TestWithModelWeights(t *testing.T) {
+	g := New("Test", WithModelWeights(map[string]float64{"squares": 0, "plaid": 1})).(*generator)
+	if g.name != "plaid" {
+		t.Errorf("The model with zero weight should never be chosen, got: %s", g.name)
+	}
+	// status
+	if len(g.errors) > 0 {
+		t.Error("There are errors in the default generator.", g.errors)
+	}
+
+	weights := map[string]float64{"squares": 3, "plaid": 1, "hexagons": 2}
+	first := New("Test", WithModelWeights(weights)).(*generator).name
+	for i := 0; i < 10; i++ {
+		if name := New("Test", WithModelWeights(weights)).(*generator).name; name != first {
+			t.Errorf("The weighted choice should be deterministic, got %s and %s.", first, name)
+		}
+	}
+
+	g.Options(WithModelWeights(map[string]float64{"squares": -1, "plaid": 1}))
+	if len(g.errors) != 1 || g.name != "plaid" {
+		t.Errorf("The negative weight should be ignored with an error, got model %s and errors %v.", g.name, g.errors)
+	}
+}
+
+func TestWithTags(t *testing.T) {
+	g := New("Test", WithModel("sin-waves", "topographic", "squares"), WithTags("organic", "subtle")).(*generator)
+	if g.name != "sin-waves" && g.name != "topographic" {
+		t.Errorf("The organic and subtle models are sin-waves and topographic, got: %s", g.name)
+	}
+	if len(g.models) != 2 {
+		t.Errorf("There should be 2 organic and subtle models, got %d.", len(g.models))
+	}
+	// status
+	if len(g.errors) > 0 {
+		t.Error("There are errors in the default generator.", g.errors)
+	}
+
+	g.Options(WithTags("bingo"))
+	if len(g.errors) != 1 || len(g.models) != len(model.EmbeddedModels) {
+		t.Errorf("Unknown tag should produce an error and use all models, got errors %v.", g.errors)
+	}
+
+	g = New("Test", WithOutputVersion("v1"), WithTags("organic", "subtle")).(*generator)
+	if g.name != "sin-waves" || len(g.errors) > 0 {
+		t.Errorf("The only organic and subtle v1 model is sin-waves, got %s and errors %v.", g.name, g.errors)
+	}
+}
+
+func TestWithoutModels(t *testing.T) {
+	g := New("Test", WithModel("squares", "plaid"), WithoutModels("plaid")).(*generator)
+	if g.name != "squares" {
+		t.Errorf("The only remaining model is squares, got: %s", g.name)
+	}
+	// status
+	if len(g.errors) > 0 {
+		t.Error("There are errors in the default generator.", g.errors)
+	}
+}
+
+func TestWithParam(t *testing.T) {
+	g := New("Test", WithModel("squares"), WithParam("size", "42"), WithParam("bingo", "1")).(*generator)
+	svg, ok := g.Generate()
+	if !ok || !strings.Contains(string(svg), `width="42" height="42"`) {
+		t.Errorf("The size parameter is not used by the squares model: %s", svg)
+	}
+
+	g = New("Test", WithModel("squares")).(*generator)
+	svg, ok = g.Generate()
+	if !ok || strings.Contains(string(svg), `width="42" height="42"`) {
+		t.Errorf("The default size should be random in [21,35]: %s", svg)
+	}
+
+	for _, tc := range []struct{ model, name, value string }{
+		{"mosaic-squares", "nx", "abc"},
+		{"mosaic-squares", "nx", "-1"},
+		{"mosaic-squares", "nx", "2.5"},
+		{"mosaic-squares", "nx", "1e9"},
+		{"sin-waves", "amplitude", "abc"},
+		{"sin-waves", "amplitude", "NaN"},
+		{"girih-octagrams", "order", "12"},
+		{"girih-octagrams", "width", `abc" onload="x`},
+		{"girih-octagrams", "width", "50"},
+		{"girih-rosettes", "width", "-1"},
+		{"voronoi-cells", "width", "abc"},
+		{"voronoi-cells", "width", "21"},
+		{"flow-field", "turns", "11"},
+		{"girih-rosettes", "order", "6"},
+	} {
+		g = New("Test", WithModel(tc.model), WithParam(tc.name, tc.value)).(*generator)
+		_, ok = g.Generate()
+		if ok || len(g.errors) != 1 {
+			t.Errorf("The %s value '%s' should produce an error, got errors %v.", tc.name, tc.value, g.errors)
+		}
+		if g.Params()[tc.name] == tc.value {
+			t.Errorf("The rejected %s value '%s' should not be used.", tc.name, tc.value)
+		}
+	}
+
+	g = New("Test", WithModel("sin-waves"), WithParam("amplitude", "2.5")).(*generator)
+	if _, ok = g.Generate(); !ok || g.Params()["amplitude"] != "2.5" {
+		t.Errorf("The amplitude 2.5 should be valid, got %v and errors %v.", g.Params(), g.errors)
+	}
+	g = New("Test", WithModel("voronoi-cells"), WithParam("width", "2.5")).(*generator)
+	if _, ok = g.Generate(); !ok || g.Params()["width"] != "2.5" {
+		t.Errorf("The width 2.5 should be valid, got %v and errors %v.", g.Params(), g.errors)
+	}
+	g = New("Test", WithModel("truchet-diagonals"), WithParam("size", "4"), WithParam("width", "20")).(*generator)
+	if _, ok = g.Generate(); ok || len(g.errors) != 1 {
+		t.Errorf("The width 20 should be too large for the size 4, got errors %v.", g.errors)
+	}
+	g = New("Test", WithModel("truchet-diagonals"), WithParam("size", "4")).(*generator)
+	var width float64
+	_, ok = g.Generate()
+	if _, err := fmt.Sscan(g.Params()["width"], &width); !ok || err != nil || width > 2.83 {
+		t.Errorf("The default width should fit the size 4, got %v and errors %v.", g.Params(), g.errors)
+	}
+	g = New("Test", WithModel("girih-octagrams"), WithParam("order", "16")).(*generator)
+	if _, ok = g.Generate(); !ok || g.Params()["order"] != "16" {
+		t.Errorf("The order 16 should be valid, got %v and errors %v.", g.Params(), g.errors)
+	}
+}
+
+func TestResolvedParams(t *testing.T) {
+	g := New("Test", WithModel("mosaic-squares"), WithParam("ny", "2"))
+	if g.Model() != "mosaic-squares" {
+		t.Errorf("The model should be mosaic-squares, got: %s", g.Model())
+	}
+	if g.Seed() != -4256609867660129692 {
+		t.Error("The random seed for 'Test' should be -4256609867660129692, but it is", g.Seed())
+	}
+	if _, ok := g.Generate(); !ok {
+		t.Error("There are errors in the generator.", g.Errors())
+	}
+	params := g.Params()
+	if len(params) != 2 || params["ny"] != "2" || params["nx"] == "" {
+		t.Errorf("The resolved parameters should contain nx and ny=2, got: %v", params)
+	}
+}
+
+func TestWithOutputVersion(t *testing.T) {
+	g1 := New("Test").(*generator)
+	g2 := New("Test", WithOutputVersion(model.LatestVersion)).(*generator)
+	if g1.seed != g2.seed || g1.color != g2.color || g1.name != g2.name {
+		t.Errorf("The latest version should be the default one, got (%d, %s, %s) and (%d, %s, %s).", g1.seed, g1.color.Hex(), g1.name, g2.seed, g2.color.Hex(), g2.name)
+	}
+	// status
+	if len(g2.errors) > 0 {
+		t.Error("There are errors in the generator.", g2.errors)
+	}
+
+	g2.Options(WithOutputVersion("v0"))
+	if len(g2.errors) != 1 || g2.version != model.LatestVersion {
+		t.Errorf("An unknown version should produce an error and be ignored, got version %s and errors %v.", g2.version, g2.errors)
+	}
+
+	g3 := New("Test", WithModel("plaid"), WithColor("#a17"), WithOutputVersion("v1")).(*generator)
+	if len(g3.errors) != 1 || g3.version != "v1" {
+		t.Errorf("A late output version should produce an error, got version %s and errors %v.", g3.version, g3.errors)
+	}
+
+	for _, version := range model.VersionNames {
+		if _, ok := outputVersions[version]; !ok {
+			t.Errorf("The seeding logic of the version %s is missing.", version)
+		}
+	}
+}
+
+func TestWithIDPrefix(t *testing.T) {
+	ids := regexp.MustCompile(`(?:id="|href="#|url\(#)([^")]*)`)
+	for _, m := range model.EmbeddedModels {
+		g := New("Test", WithModel(m.Meta().Name), WithIDPrefix("x-"), WithAvatar(64, "circle"))
+		svg, ok := g.Generate()
+		if !ok {
+			t.Error("There are errors in the generator.", g.Errors())
+		}
+		for _, id := range ids.FindAllStringSubmatch(string(svg), -1) {
+			if !strings.HasPrefix(id[1], "x-") {
+				t.Errorf("The id %s of the model %s is not prefixed.", id[1], m.Meta().Name)
+			}
+		}
+	}
+
+	g1 := New("Test", WithIDPrefix("")).(*generator)
+	g2 := New("Test", WithIDPrefix("")).(*generator)
+	g3 := New("Other", WithIDPrefix("1-bad")).(*generator)
+	if g1.idPrefix == "" || g1.idPrefix != g2.idPrefix {
+		t.Errorf("The phrase derived prefix should be reproducible, got %s and %s.", g1.idPrefix, g2.idPrefix)
+	}
+	if len(g3.errors) != 1 || g3.idPrefix == g1.idPrefix || !validID.MatchString(g3.idPrefix) {
+		t.Errorf("An invalid prefix should produce an error and use a phrase derived one, got %s and errors %v.", g3.idPrefix, g3.errors)
+	}
+
+	prefixes := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		g := New(fmt.Sprint("phrase", i), WithOutputVersion("v1")).(*generator)
+		prefixes[g.phrasePrefix()] = true
+	}
+	if len(prefixes) != 1000 {
+		t.Errorf("1000 phrases should give 1000 id prefixes, got %d.", len(prefixes))
+	}
+
+	g := New("Test", WithOutputVersion("v1"), WithIDPrefix("x-")).(*generator)
+	if len(g.errors) != 1 || g.idPrefix != "" {
+		t.Errorf("The v1 models should not accept an id prefix, got %s and errors %v.", g.idPrefix, g.errors)
+	}
+	g = New("Test", WithOutputVersion("v1"), WithPalette("#000", "#fff")).(*generator)
+	if len(g.errors) != 1 || g.dark != defaultDark {
+		t.Errorf("The v1 models should not accept a palette, got %s and errors %v.", g.dark, g.errors)
+	}
+}
+
+// A goModel renders a circle with random radius, as go model.
+type goModel struct{}
+
+func (goModel) Render(w io.Writer, r *rand.Rand, data model.Data) error {
+	size := data.Float("size", 10, 1, 100)
+	_, err := fmt.Fprintf(w, `<svg><circle id="%scircle" r="%v" cx="%d" fill="%s"/></svg>`, data.ID, size, r.Intn(100), data.Dark)
+	return err
+}
+
+func TestGoModel(t *testing.T) {
+	saved, savedEmbedded := make(map[string]model.Models), model.EmbeddedModels
+	for v, models := range model.Versions {
+		saved[v] = models
+	}
+	defer func() { model.Versions, model.EmbeddedModels = saved, savedEmbedded }()
+	if err := model.Register(model.LatestVersion, model.Metadata{Name: "go-circle"}, goModel{}); err != nil {
+		t.Fatal(err)
+	}
+
+	g := New("Test", WithModel("go-circle"), WithParam("size", "5"), WithIDPrefix("x-"), WithPalette("#000", "#fff"))
+	svg, ok := g.Generate()
+	if !ok || g.Model() != "go-circle" {
+		t.Fatal("The go model should be selected.", g.Model(), g.Errors())
+	}
+	if !strings.Contains(string(svg), `<circle id="x-circle" r="5"`) || !strings.Contains(string(svg), `fill="#000"`) {
+		t.Errorf("The go model should use the data, got: %s", svg)
+	}
+	if g.Params()["size"] != "5" {
+		t.Errorf("The go model parameters should be resolved, got: %v", g.Params())
+	}
+	again, _ := New("Test", WithModel("go-circle"), WithParam("size", "5"), WithIDPrefix("x-"), WithPalette("#000", "#fff")).Generate()
+	if string(again) != string(svg) {
+		t.Errorf("The go model should be reproducible, got %s and %s", svg, again)
+	}
+
+	g = New("Test", WithModel("go-circle"), WithParam("size", "1e9"))
+	svg, ok = g.Generate()
+	if ok || len(g.Errors()) != 1 || !strings.Contains(string(svg), `r="10"`) {
+		t.Errorf("An out of range parameter should produce an error and use the default value, got %s and errors %v.", svg, g.Errors())
+	}
+}
@@ -0,0 +1,196 @@
+package svgpattern
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// A seamPolygon is a filled polygon of a rendered pattern.
+// The label (fill and opacity) tells apart the pattern elements
+// and copy is true for the translated copies.
+type seamPolygon struct {
+	points [][2]float64
+	label  string
+	copy   bool
+}
+
+// contains verifies (with the even-odd rule) if the point is inside the polygon.
+func (p seamPolygon) contains(x, y float64) bool {
+	in := false
+	for i, j := 0, len(p.points)-1; i < len(p.points); j, i = i, i+1 {
+		a, b := p.points[i], p.points[j]
+		if (a[1] > y) != (b[1] > y) && x < (b[0]-a[0])*(y-a[1])/(b[1]-a[1])+a[0] {
+			in = !in
+		}
+	}
+	return in
+}
+
+// parseTransform reads the 'translate(x,y)' and 'rotate(a)' transforms.
+func parseTransform(s string) (f func([2]float64) [2]float64, moved bool) {
+	f = func(p [2]float64) [2]float64 { return p }
+	name, args, _ := strings.Cut(strings.TrimSuffix(strings.TrimSpace(s), ")"), "(")
+	var v []float64
+	for _, a := range strings.FieldsFunc(args, func(r rune) bool { return r == ',' || r == ' ' }) {
+		x, _ := strconv.ParseFloat(a, 64)
+		v = append(v, x)
+	}
+	switch {
+	case name == "translate" && len(v) == 2:
+		return func(p [2]float64) [2]float64 { return [2]float64{p[0] + v[0], p[1] + v[1]} }, v[0] != 0 || v[1] != 0
+	case name == "rotate" && len(v) == 1:
+		sin, cos := math.Sincos(v[0] * math.Pi / 180)
+		return func(p [2]float64) [2]float64 { return [2]float64{p[0]*cos - p[1]*sin, p[0]*sin + p[1]*cos} }, false
+	}
+	return f, false
+}
+
+// seamPolygons reads the polygons of the pattern of a rendered svg (directly
+// or through <use> of a group of polygons) and the pattern dimensions.
+func seamPolygons(svg []byte) (polygons []seamPolygon, width, height float64, err error) {
+	attrs := func(e xml.StartElement) map[string]string {
+		m := make(map[string]string)
+		for _, a := range e.Attr {
+			m[a.Name.Local] = a.Value
+		}
+		return m
+	}
+	groups := make(map[string][]map[string]string)
+	group := ""
+	inPattern := false
+	// polygon provides the polygon of the attributes a, inside the element with attributes parent.
+	polygon := func(a, parent map[string]string) seamPolygon {
+		t, moved := parseTransform(a["transform"])
+		pt, pmoved := parseTransform(parent["transform"])
+		p := seamPolygon{copy: moved || pmoved}
+		for _, xy := range strings.Fields(a["points"]) {
+			xs, ys, _ := strings.Cut(xy, ",")
+			x, _ := strconv.ParseFloat(xs, 64)
+			y, _ := strconv.ParseFloat(ys, 64)
+			p.points = append(p.points, pt(t([2]float64{x, y})))
+		}
+		fill, opacity := a["fill"], a["fill-opacity"]
+		if fill == "" {
+			fill, opacity = parent["fill"], parent["fill-opacity"]
+		}
+		p.label = fill + "/" + opacity
+		return p
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+		switch e := tok.(type) {
+		case xml.StartElement:
+			a := attrs(e)
+			switch {
+			case e.Name.Local == "pattern":
+				inPattern = true
+				width, _ = strconv.ParseFloat(a["width"], 64)
+				height, _ = strconv.ParseFloat(a["height"], 64)
+			case e.Name.Local == "g" && !inPattern && a["id"] != "":
+				group = a["id"]
+			case e.Name.Local == "polygon" && group != "":
+				groups[group] = append(groups[group], a)
+			case e.Name.Local == "polygon" && inPattern:
+				polygons = append(polygons, polygon(a, nil))
+			case e.Name.Local == "use" && inPattern:
+				ref := strings.TrimPrefix(a["href"], "#")
+				if _, ok := groups[ref]; !ok {
+					return nil, 0, 0, fmt.Errorf("unknown group %s", ref)
+				}
+				for _, ga := range groups[ref] {
+					polygons = append(polygons, polygon(ga, a))
+				}
+			}
+		case xml.EndElement:
+			switch e.Name.Local {
+			case "pattern":
+				inPattern = false
+			case "g":
+				group = ""
+			}
+		}
+	}
+	if width <= 0 || height <= 0 || len(polygons) == 0 {
+		return nil, 0, 0, fmt.Errorf("missing pattern polygons")
+	}
+
+	return polygons, width, height, nil
+}
+
+// seamErrors rasterizes the pixels around the width×height tile (at distance less than r),
+// and verifies that the polygons drawn there are also drawn at the same place in the tile.
+// As the pattern is clipped to the tile, the other pixels are lost at the seams.
+// It provides the number of different pixels, that are zero if the pattern is seamless.
+func seamErrors(polygons []seamPolygon, width, height, r float64) (errors int) {
+	labels := func(x, y float64) []string {
+		var l []string
+		for _, p := range polygons {
+			if p.contains(x, y) {
+				l = append(l, p.label)
+			}
+		}
+		sort.Strings(l)
+		return l
+	}
+	for x := -r + 0.5; x < width+r; x++ {
+		for y := -r + 0.5; y < height+r; y++ {
+			if x >= 0 && x < width && y >= 0 && y < height {
+				continue
+			}
+			outside := labels(x, y)
+			inside := labels(x-width*math.Floor(x/width), y-height*math.Floor(y/height))
+			// the outside labels should be a sub-list of the inside ones
+			i := 0
+			for _, l := range inside {
+				if i < len(outside) && outside[i] == l {
+					i++
+				}
+			}
+			if i < len(outside) {
+				errors++
+			}
+		}
+	}
+
+	return errors
+}
+
+func TestModelSeams(t *testing.T) {
+	for _, name := range []string{"girih-octagrams", "girih-rosettes", "voronoi-cells", "low-poly"} {
+		for _, phrase := range goldenPhrases {
+			g := New(phrase, WithModel(name))
+			svg, ok := g.Generate()
+			if !ok {
+				t.Fatal("There are errors in the generator.", g.Errors())
+			}
+			polygons, width, height, err := seamPolygons(svg)
+			if err != nil {
+				t.Fatalf("Error reading the polygons of %s: %v", name, err)
+			}
+			if n := seamErrors(polygons, width, height, 8); n > 0 {
+				t.Errorf("The pattern %s of %s should be seamless, got %d different pixels.", name, phrase, n)
+			}
+			// the harness detects the seams without the copies
+			var originals []seamPolygon
+			for _, p := range polygons {
+				if !p.copy {
+					originals = append(originals, p)
+				}
+			}
+			if n := seamErrors(originals, width, height, 8); n == 0 {
+				t.Errorf("The pattern %s of %s without copies should not be seamless.", name, phrase)
+			}
+		}
+	}
+}
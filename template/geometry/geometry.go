@@ -0,0 +1,193 @@
+// Package geometry provides the Delaunay triangulation and the Voronoi diagram
+// of points on the plane and on the torus (for seamless patterns).
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// A Point of the plane.
+type Point struct {
+	X, Y float64
+}
+
+// A Triangle of the plane.
+type Triangle [3]Point
+
+// Centroid provides the center of mass of the triangle.
+func (t Triangle) Centroid() Point {
+	return Point{(t[0].X + t[1].X + t[2].X) / 3, (t[0].Y + t[1].Y + t[2].Y) / 3}
+}
+
+// circumcircle provides the center and the squared radius of the circle through a, b and c.
+func circumcircle(a, b, c Point) (center Point, r2 float64) {
+	d := 2 * (a.X*(b.Y-c.Y) + b.X*(c.Y-a.Y) + c.X*(a.Y-b.Y))
+	if d == 0 {
+		// aligned points: the circle is at infinity
+		return Point{math.Inf(1), math.Inf(1)}, math.Inf(1)
+	}
+	a2, b2, c2 := a.X*a.X+a.Y*a.Y, b.X*b.X+b.Y*b.Y, c.X*c.X+c.Y*c.Y
+	center.X = (a2*(b.Y-c.Y) + b2*(c.Y-a.Y) + c2*(a.Y-b.Y)) / d
+	center.Y = (a2*(c.X-b.X) + b2*(a.X-c.X) + c2*(b.X-a.X)) / d
+	dx, dy := a.X-center.X, a.Y-center.Y
+
+	return center, dx*dx + dy*dy
+}
+
+// A triangulation triangle is the indices of its vertices and its circumcircle.
+type triangle struct {
+	v      [3]int
+	center Point
+	r2     float64
+}
+
+// triangulate provides the Delaunay triangles (as vertex indices) of the points,
+// computed with the Bowyer-Watson algorithm.
+func triangulate(points []Point) []triangle {
+	if len(points) < 3 {
+		return nil
+	}
+	// the super triangle that contains all the points
+	minX, minY, maxX, maxY := points[0].X, points[0].Y, points[0].X, points[0].Y
+	for _, p := range points {
+		minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+		maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+	}
+	d := math.Max(maxX-minX, maxY-minY) * 20
+	mx, my := (minX+maxX)/2, (minY+maxY)/2
+	n := len(points)
+	all := append(append([]Point(nil), points...),
+		Point{mx - d, my - d}, Point{mx + d, my - d}, Point{mx, my + d})
+
+	newTriangle := func(a, b, c int) triangle {
+		center, r2 := circumcircle(all[a], all[b], all[c])
+		return triangle{[3]int{a, b, c}, center, r2}
+	}
+	triangles := []triangle{newTriangle(n, n+1, n+2)}
+	for i, p := range points {
+		// the triangles whose circumcircle contains p are removed
+		// and the hole is filled with triangles from p to the hole boundary
+		edges := make(map[[2]int]int)
+		var order [][2]int
+		kept := triangles[:0]
+		for _, t := range triangles {
+			dx, dy := p.X-t.center.X, p.Y-t.center.Y
+			if dx*dx+dy*dy >= t.r2 {
+				kept = append(kept, t)
+				continue
+			}
+			for k := 0; k < 3; k++ {
+				a, b := t.v[k], t.v[(k+1)%3]
+				if a > b {
+					a, b = b, a
+				}
+				if edges[[2]int{a, b}] == 0 {
+					order = append(order, [2]int{a, b})
+				}
+				edges[[2]int{a, b}]++
+			}
+		}
+		triangles = kept
+		for _, e := range order {
+			if edges[e] == 1 {
+				triangles = append(triangles, newTriangle(e[0], e[1], i))
+			}
+		}
+	}
+
+	// remove the triangles of the super triangle
+	result := triangles[:0]
+	for _, t := range triangles {
+		if t.v[0] < n && t.v[1] < n && t.v[2] < n {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// Delaunay provides the Delaunay triangulation of the points:
+// the triangles with no point inside their circumcircle.
+func Delaunay(points []Point) []Triangle {
+	triangles := triangulate(points)
+	result := make([]Triangle, len(triangles))
+	for i, t := range triangles {
+		result[i] = Triangle{points[t.v[0]], points[t.v[1]], points[t.v[2]]}
+	}
+
+	return result
+}
+
+// torus provides the 9 copies of the points (moved in the width×height tile),
+// the original points being the first ones.
+func torus(points []Point, width, height float64) []Point {
+	copies := make([]Point, 0, 9*len(points))
+	for _, d := range [][2]float64{{0, 0}, {-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}} {
+		for _, p := range points {
+			x, y := math.Mod(p.X, width), math.Mod(p.Y, height)
+			if x < 0 {
+				x += width
+			}
+			if y < 0 {
+				y += height
+			}
+			copies = append(copies, Point{x + d[0]*width, y + d[1]*height})
+		}
+	}
+
+	return copies
+}
+
+// TorusDelaunay provides the Delaunay triangulation of the points on the
+// width×height torus (the points are first moved inside the tile).
+// Each triangle is provided once, as the copy with centroid inside the tile,
+// so some triangles cross the tile bounds.
+// The triangles are sorted by centroid (from top to bottom, then left to right).
+func TorusDelaunay(points []Point, width, height float64) []Triangle {
+	if len(points) == 0 || width <= 0 || height <= 0 {
+		return nil
+	}
+	var result []Triangle
+	for _, t := range Delaunay(torus(points, width, height)) {
+		c := t.Centroid()
+		if c.X >= 0 && c.X < width && c.Y >= 0 && c.Y < height {
+			result = append(result, t)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ci, cj := result[i].Centroid(), result[j].Centroid()
+		if ci.Y != cj.Y {
+			return ci.Y < cj.Y
+		}
+		return ci.X < cj.X
+	})
+
+	return result
+}
+
+// TorusVoronoi provides the Voronoi cells of the points on the width×height torus.
+// The cell i is the polygon (in clockwise order on the screen) around the point i
+// moved inside the tile, so some cells cross the tile bounds.
+func TorusVoronoi(points []Point, width, height float64) [][]Point {
+	if len(points) == 0 || width <= 0 || height <= 0 {
+		return nil
+	}
+	all := torus(points, width, height)
+	cells := make([][]Point, len(points))
+	for _, t := range triangulate(all) {
+		for _, v := range t.v {
+			if v < len(points) {
+				cells[v] = append(cells[v], t.center)
+			}
+		}
+	}
+	for i, cell := range cells {
+		site := all[i]
+		sort.Slice(cell, func(a, b int) bool {
+			return math.Atan2(cell[a].Y-site.Y, cell[a].X-site.X) < math.Atan2(cell[b].Y-site.Y, cell[b].X-site.X)
+		})
+	}
+
+	return cells
+}
@@ -0,0 +1,98 @@
+package geometry
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randomPoints provides n random points in the width×height tile.
+func randomPoints(n int, width, height float64) []Point {
+	r := rand.New(rand.NewSource(42))
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{r.Float64() * width, r.Float64() * height}
+	}
+
+	return points
+}
+
+// area provides the (positive) area of the polygon.
+func area(polygon []Point) float64 {
+	a := 0.0
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		a += p.X*q.Y - q.X*p.Y
+	}
+
+	return math.Abs(a) / 2
+}
+
+func TestDelaunay(t *testing.T) {
+	points := randomPoints(50, 100, 100)
+	triangles := Delaunay(points)
+	if len(triangles) == 0 {
+		t.Fatal("there should be triangles")
+	}
+	for _, tr := range triangles {
+		center, r2 := circumcircle(tr[0], tr[1], tr[2])
+		for _, p := range points {
+			dx, dy := p.X-center.X, p.Y-center.Y
+			if dx*dx+dy*dy < r2-1e-9 {
+				t.Fatalf("the point %v is inside the circumcircle of %v", p, tr)
+			}
+		}
+	}
+	if len(Delaunay(points[:2])) != 0 {
+		t.Error("there should be no triangles for two points")
+	}
+}
+
+func TestCircumcircle(t *testing.T) {
+	center, r2 := circumcircle(Point{0, 0}, Point{2, 0}, Point{0, 2})
+	if center != (Point{1, 1}) || r2 != 2 {
+		t.Errorf("the circumcircle should be (1,1) with r²=2, got %v with %f", center, r2)
+	}
+	if _, r2 := circumcircle(Point{0, 0}, Point{1, 1}, Point{2, 2}); !math.IsInf(r2, 1) {
+		t.Errorf("the circumcircle of aligned points should be infinite, got r²=%f", r2)
+	}
+}
+
+func TestTorusDelaunay(t *testing.T) {
+	n, w, h := 40, 120.0, 80.0
+	triangles := TorusDelaunay(randomPoints(n, w, h), w, h)
+	// on the torus V - E + F = 0 and 2E = 3F, so F = 2V
+	if len(triangles) != 2*n {
+		t.Errorf("there should be %d triangles on the torus, got %d", 2*n, len(triangles))
+	}
+	total := 0.0
+	for _, tr := range triangles {
+		total += area(tr[:])
+	}
+	if math.Abs(total-w*h) > 1e-6 {
+		t.Errorf("the triangles should cover the tile area %f, got %f", w*h, total)
+	}
+}
+
+func TestTorusVoronoi(t *testing.T) {
+	n, w, h := 40, 120.0, 80.0
+	points := randomPoints(n, w, h)
+	points[0].X -= w // the points are moved inside the tile
+	cells := TorusVoronoi(points, w, h)
+	if len(cells) != n {
+		t.Fatalf("there should be %d cells, got %d", n, len(cells))
+	}
+	total := 0.0
+	for i, cell := range cells {
+		if len(cell) < 3 {
+			t.Errorf("the cell %d should be a polygon, got %v", i, cell)
+		}
+		total += area(cell)
+	}
+	if math.Abs(total-w*h) > 1e-6 {
+		t.Errorf("the cells should cover the tile area %f, got %f", w*h, total)
+	}
+	if len(TorusVoronoi(nil, w, h)) != 0 || len(TorusVoronoi(points, 0, h)) != 0 {
+		t.Error("there should be no cells without points or tile")
+	}
+}
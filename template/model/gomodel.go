@@ -0,0 +1,107 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+// Data is the data of a pattern generation.
+// It is the data (dot) of the template models and the data of the go models.
+type Data struct {
+	// background color and opacity
+	Color   string
+	Opacity float64
+	// pattern transformation
+	Rotate float64
+	Scale  float64
+	// model parameters set by the user
+	Params map[string]string
+	// prefix of all element ids
+	ID string
+	// palette of the pattern elements
+	Dark  string
+	Light string
+	// Param provides the value of the model parameter if set by the user and valid,
+	// else the provided default value (like the `param` template function).
+	Param func(name string, value interface{}, limits ...interface{}) interface{}
+}
+
+// Float provides the value of the model parameter as number in [min, max] (see Param).
+// The invalid values are reported by Param and the default value is used instead.
+func (d Data) Float(name string, value, min, max float64) float64 {
+	if d.Param == nil {
+		return value
+	}
+	if f, ok := d.Param(name, value, min, max).(float64); ok {
+		return f
+	}
+
+	return value
+}
+
+// Int provides the value of the model parameter as integer in [min, max] (see Float).
+func (d Data) Int(name string, value, min, max int) int {
+	if d.Param == nil {
+		return value
+	}
+	if n, ok := d.Param(name, value, min, max).(int); ok {
+		return n
+	}
+
+	return value
+}
+
+// Renderer is a pattern model written in go,
+// for the patterns impractical to write as go-template.
+type Renderer interface {
+	// Render writes the svg pattern to w. All random values should be drawn from r,
+	// so that the pattern is reproducible. The pattern should respect the data,
+	// like a template model (background, transformation, palette and ids).
+	Render(w io.Writer, r *rand.Rand, data Data) error
+}
+
+// GoModel represent a go model with metadata and renderer.
+type GoModel struct {
+	Metadata
+	Renderer
+}
+
+// Meta provides the model metadata.
+func (m GoModel) Meta() Metadata {
+	return m.Metadata
+}
+
+// Register adds (or replace) the go model with the provided metadata and renderer
+// to the output version and all the following versions.
+// Note that the released output versions should not be modified.
+func Register(version string, meta Metadata, r Renderer) error {
+	if _, ok := Versions[version]; !ok {
+		return fmt.Errorf("unknown output version '%s'", version)
+	}
+	if meta.Name == "" || r == nil {
+		return fmt.Errorf("a go model needs a name and a renderer")
+	}
+
+	for _, v := range VersionNames {
+		if versionNumber(v) < versionNumber(version) {
+			continue
+		}
+		models := append(Models(nil), Versions[v]...)
+		models.set(GoModel{meta, r})
+		Versions[v] = models
+	}
+	EmbeddedModels = Versions[LatestVersion]
+
+	return nil
+}
+
+// builtinGoModels are the go models of the module,
+// with the output version that introduced them.
+var builtinGoModels = []struct {
+	version  string
+	meta     Metadata
+	renderer Renderer
+}{
+	{"v2", mazeMetadata, maze{}},
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// maze is the go model of a random perfect maze on the torus.
+type maze struct{}
+
+// mazeMetadata is the metadata of the maze model.
+var mazeMetadata = Metadata{
+	Name:        "maze",
+	Description: "Seamless random perfect maze with stroked walls.",
+	Author:      "kpym",
+	License:     "MIT",
+	Width:       20,
+	Height:      20,
+	Tags:        []string{"geometric", "busy"},
+	Params: []Param{
+		{"n", "number of cells per side (2 to 64, 8 to 14 by default)"},
+		{"size", "the side of the cells (4 to 200, 16 to 24 by default)"},
+		{"width", "thickness of the walls (0.5 to 20, 2 to 4 by default)"},
+	},
+}
+
+// torusMaze provides a random perfect maze (a spanning tree of the cells)
+// on the n×n torus, built with the recursive backtracker algorithm.
+// right[i][j] is true if the cell (i,j) is open to the cell (i+1,j), and
+// down[i][j] is true if the cell (i,j) is open to the cell (i,j+1), modulo n.
+func torusMaze(r *rand.Rand, n int) (right, down [][]bool) {
+	right, down = make([][]bool, n), make([][]bool, n)
+	visited := make([][]bool, n)
+	for i := 0; i < n; i++ {
+		right[i], down[i], visited[i] = make([]bool, n), make([]bool, n), make([]bool, n)
+	}
+	// the neighbour of the cell (i,j) in the direction d, and the passage between them
+	neighbour := func(i, j, d int) (ni, nj int, passage *bool) {
+		switch d {
+		case 0:
+			ni, nj = (i+1)%n, j
+			passage = &right[i][j]
+		case 1:
+			ni, nj = i, (j+1)%n
+			passage = &down[i][j]
+		case 2:
+			ni, nj = (i+n-1)%n, j
+			passage = &right[ni][j]
+		default:
+			ni, nj = i, (j+n-1)%n
+			passage = &down[i][nj]
+		}
+		return
+	}
+
+	stack := [][2]int{{r.Intn(n), r.Intn(n)}}
+	visited[stack[0][0]][stack[0][1]] = true
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		var next []int
+		for d := 0; d < 4; d++ {
+			ni, nj, _ := neighbour(c[0], c[1], d)
+			if !visited[ni][nj] {
+				next = append(next, d)
+			}
+		}
+		if len(next) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		ni, nj, passage := neighbour(c[0], c[1], next[r.Intn(len(next))])
+		*passage = true
+		visited[ni][nj] = true
+		stack = append(stack, [2]int{ni, nj})
+	}
+
+	return right, down
+}
+
+// mazeTemplate is the svg code of the maze, with the walls as path (.Walls).
+var mazeTemplate = template.Must(template.New("maze").Parse(`<svg width="100%" height="100%" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">
+  <defs>
+    <pattern  id="{{ .ID }}pattern" patternTransform="rotate({{ .Rotate }}) scale({{ .Scale }})" x="0" y="0" width="{{ .Size }}" height="{{ .Size }}" patternUnits="userSpaceOnUse">
+      <path d="{{ .Walls }}" fill="none" stroke="{{ .Stroke }}" stroke-opacity="{{ .StrokeOpacity }}" stroke-width="{{ .Width }}" stroke-linecap="square"/>
+    </pattern>
+  </defs>
+
+  {{- if gt .Opacity 0.0 }}
+  <rect fill="{{ .Color }}" height="100%" width="100%" x="0" y="0" {{ if lt .Opacity 1.0 }}fill-opacity="{{ .Opacity }}"{{ end }}/>
+  {{- end }}
+  <rect fill="url(#{{ .ID }}pattern)" height="100%" width="100%" x="0" y="0"/>
+</svg>
+`))
+
+// round2 prints the number rounded to 2 digits, without the non significant zeros.
+func round2(x float64) string {
+	return strconv.FormatFloat(math.Round(x*100)/100, 'f', -1, 64)
+}
+
+// Render writes the maze pattern.
+func (maze) Render(w io.Writer, r *rand.Rand, data Data) error {
+	n := data.Int("n", 8+r.Intn(7), 2, 64)
+	size := data.Float("size", float64(16+r.Intn(9)), 4, 200)
+	width := data.Float("width", float64(2+r.Intn(3)), 0.5, 20)
+	if n < 2 || size <= 0 || width <= 0 {
+		return fmt.Errorf("invalid maze parameters n=%d, size=%v and width=%v", n, size, width)
+	}
+	stroke := data.Dark
+	if r.Intn(2) == 1 {
+		stroke = data.Light
+	}
+	opacity := math.Round((0.15+0.15*r.Float64())*100) / 100
+
+	right, down := torusMaze(r, n)
+	total := float64(n) * size
+	var walls []string
+	// wall adds the wall segment from (x,y) (horizontal or vertical), and its copies
+	// at the opposite sides of the pattern if it crosses the pattern bounds
+	wall := func(x, y float64, horizontal bool) {
+		x1, y1 := x, y
+		segment := "h" + round2(size)
+		if horizontal {
+			x1 += size
+		} else {
+			y1 += size
+			segment = "v" + round2(size)
+		}
+		copies := func(a, b float64) []float64 {
+			c := []float64{0}
+			if a-width/2 < 0 {
+				c = append(c, total)
+			}
+			if b+width/2 > total {
+				c = append(c, -total)
+			}
+			return c
+		}
+		for _, dy := range copies(y, y1) {
+			for _, dx := range copies(x, x1) {
+				walls = append(walls, "M"+round2(x+dx)+","+round2(y+dy)+segment)
+			}
+		}
+	}
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			x, y := float64(i)*size, float64(j)*size
+			if !right[i][j] {
+				wall(x+size, y, false)
+			}
+			if !down[i][j] {
+				wall(x, y+size, true)
+			}
+		}
+	}
+
+	return mazeTemplate.Execute(w, struct {
+		Data
+		Rotate, Scale, Size string
+		Walls               string
+		Stroke              string
+		StrokeOpacity       float64
+		Width               float64
+	}{data, round2(data.Rotate), round2(data.Scale), round2(total), strings.Join(walls, " "), stroke, opacity, width})
+}
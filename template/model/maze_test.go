@@ -0,0 +1,78 @@
+package model
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestTorusMaze(t *testing.T) {
+	for _, n := range []int{2, 5, 12} {
+		right, down := torusMaze(rand.New(rand.NewSource(42)), n)
+		// a perfect maze is a spanning tree: n²-1 passages and all cells connected
+		passages := 0
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if right[i][j] {
+					passages++
+				}
+				if down[i][j] {
+					passages++
+				}
+			}
+		}
+		if passages != n*n-1 {
+			t.Errorf("The %dx%d maze should have %d passages, got %d.", n, n, n*n-1, passages)
+		}
+		visited := map[[2]int]bool{{0, 0}: true}
+		queue := [][2]int{{0, 0}}
+		for len(queue) > 0 {
+			i, j := queue[0][0], queue[0][1]
+			queue = queue[1:]
+			for _, nb := range []struct {
+				open bool
+				cell [2]int
+			}{
+				{right[i][j], [2]int{(i + 1) % n, j}},
+				{down[i][j], [2]int{i, (j + 1) % n}},
+				{right[(i+n-1)%n][j], [2]int{(i + n - 1) % n, j}},
+				{down[i][(j+n-1)%n], [2]int{i, (j + n - 1) % n}},
+			} {
+				if nb.open && !visited[nb.cell] {
+					visited[nb.cell] = true
+					queue = append(queue, nb.cell)
+				}
+			}
+		}
+		if len(visited) != n*n {
+			t.Errorf("All the %d cells of the maze should be connected, got %d.", n*n, len(visited))
+		}
+	}
+}
+
+func TestMazeRender(t *testing.T) {
+	data := Data{ID: "x-", Dark: "#000", Light: "#000", Scale: 1, Opacity: 1, Color: "#fff"}
+	var svg, again bytes.Buffer
+	if err := (maze{}).Render(&svg, rand.New(rand.NewSource(42)), data); err != nil {
+		t.Fatal(err)
+	}
+	(maze{}).Render(&again, rand.New(rand.NewSource(42)), data)
+	if svg.String() != again.String() {
+		t.Error("The maze should be reproducible.")
+	}
+	s := svg.String()
+	if !strings.Contains(s, `<pattern  id="x-pattern"`) || !strings.Contains(s, `stroke="#000"`) || !strings.Contains(s, `<rect fill="#fff"`) {
+		t.Errorf("The maze should use the data, got: %s", s)
+	}
+
+	data.Param = func(name string, value interface{}, limits ...interface{}) interface{} {
+		if name == "n" {
+			return 1
+		}
+		return value
+	}
+	if err := (maze{}).Render(&svg, rand.New(rand.NewSource(42)), data); err == nil {
+		t.Error("A maze with one cell should be an error.")
+	}
+}
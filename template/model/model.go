@@ -1,30 +1,112 @@
 // Package model provides the pattern templates for svgpattern.
+//
+// # Versions
+//
+// The embedded models are organized by output version in svgmodels/<version>/.
+// A version contains all the models of the previous version, plus the models
+// from its own folder that are new or replace a previous one.
+// Once released, the folder of a version is frozen, so that the patterns
+// generated with this version never change. Any modification of a model
+// must be done in the folder of a new version.
+// The v1 models are the first released ones, without header nor parameters.
+// Their metadata is in sidecar files <name>.meta next to the templates,
+// with the same 'key: value' lines as the headers (see parseHeader).
+//
+// # Go models
+//
+// Some patterns are impractical to write as go-template. They can be written
+// in go as Renderer, and added to the models with Register.
+//
+// # Template data
+//
+// The data of the models is Data.
+// Since v2, the models should use $.ID as prefix of all their element ids,
+// and $.Dark and $.Light as colors of the pattern elements.
+// The elements that cross the pattern bounds should be repeated at the opposite
+// side to tile seamlessly, for example with the `grid` or `wrap` template functions.
 package model
 
 import (
 	"embed"
+	"fmt"
 	"io/fs"
+	"sort"
+	"strconv"
 	"strings"
 )
 
-// Model represent a go-template model with name and svg code.
-type Model struct {
-	Name string
+// Metadata describes a model.
+// Except the name, the metadata is read from the header of the model code (see parseHeader)
+// and from the optional sidecar file <name>.meta.
+type Metadata struct {
+	Name        string
+	Description string
+	Author      string
+	License     string
+	// default tile dimensions
+	Width  float64
+	Height float64
+	// tags like 'geometric', 'organic', 'busy' or 'subtle'
+	Tags []string
+	// supported parameters
+	Params []Param
+}
+
+// Param describes a model parameter.
+type Param struct {
+	Name        string
+	Description string
+}
+
+// Model is a pattern model: a go-template (TemplateModel) or a go code (GoModel).
+type Model interface {
+	// Meta provides the model metadata.
+	Meta() Metadata
+}
+
+// TemplateModel represent a go-template model with metadata and svg code.
+type TemplateModel struct {
+	Metadata
 	Code string
 }
 
+// Meta provides the model metadata.
+func (m TemplateModel) Meta() Metadata {
+	return m.Metadata
+}
+
+// HasTag verifies if the model has the provided tag.
+func (m Metadata) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Models is just a list of models.
 type Models []Model
 
-// EmbeddedModels is the list of all available models.
+// EmbeddedModels is the list of all available models (of the latest version).
 var EmbeddedModels Models
 
+// Versions are the embedded models of each output version.
+var Versions = make(map[string]Models)
+
+// VersionNames is the ordered list of the output versions, the latest being the last.
+var VersionNames []string
+
+// LatestVersion is the name of the latest output version.
+var LatestVersion string
+
 // ModelsString provide the list of all available models as string.
 func (models Models) ModelsString() string {
 	n := len(models)
 	s := make([]string, n, n)
 	for i, m := range models {
-		s[i] = m.Name
+		s[i] = m.Meta().Name
 	}
 
 	return strings.Join(s, ", ")
@@ -34,7 +116,7 @@ func (models Models) ModelsString() string {
 // If the model is not found the ok is false and indes is -1
 func (models Models) GetModelIndex(name string) (index int, ok bool) {
 	for i, m := range models {
-		if m.Name == name {
+		if m.Meta().Name == name {
 			return i, true
 		}
 	}
@@ -57,28 +139,233 @@ func (models Models) SelectModels(names ...string) (newModels Models, invalid []
 	return
 }
 
-// SetModel append or replace an existing model.
+// Filter provides the models that have all the provided tags.
+func (models Models) Filter(tags ...string) (newModels Models) {
+	for _, m := range models {
+		all := true
+		for _, tag := range tags {
+			all = all && m.Meta().HasTag(tag)
+		}
+		if all {
+			newModels = append(newModels, m)
+		}
+	}
+
+	return
+}
+
+// Exclude provides the models whose name is not in the provided list.
+func (models Models) Exclude(names ...string) (newModels Models) {
+	for _, m := range models {
+		excluded := false
+		for _, name := range names {
+			excluded = excluded || m.Meta().Name == name
+		}
+		if !excluded {
+			newModels = append(newModels, m)
+		}
+	}
+
+	return
+}
+
+// ModelsDescription provide the list of all available models with their description.
+// If long is true all metadata is provided.
+func (models Models) ModelsDescription(long bool) string {
+	var b strings.Builder
+	for _, model := range models {
+		m := model.Meta()
+		fmt.Fprintf(&b, "%-20s %s\n", m.Name, m.Description)
+		if !long {
+			continue
+		}
+		if m.Author != "" {
+			fmt.Fprintf(&b, "%-20s author: %s\n", "", m.Author)
+		}
+		if m.License != "" {
+			fmt.Fprintf(&b, "%-20s license: %s\n", "", m.License)
+		}
+		if m.Width > 0 && m.Height > 0 {
+			fmt.Fprintf(&b, "%-20s tile: %vx%v\n", "", m.Width, m.Height)
+		}
+		if len(m.Tags) > 0 {
+			fmt.Fprintf(&b, "%-20s tags: %s\n", "", strings.Join(m.Tags, ", "))
+		}
+		for _, p := range m.Params {
+			fmt.Fprintf(&b, "%-20s param %s: %s\n", "", p.Name, p.Description)
+		}
+	}
+
+	return b.String()
+}
+
+// SetModel append or replace an existing model by the template model
+// with the provided name and code.
 func (models *Models) SetModel(name string, code string) {
-	i, ok := models.GetModelIndex(name)
+	models.setTemplate(name, code, parseHeader(code))
+}
+
+// setTemplate append or replace an existing model by the template model
+// with the provided name, code and metadata fields.
+func (models *Models) setTemplate(name string, code string, fields []headerField) {
+	m := TemplateModel{Code: code}
+	m.Name = name
+	for _, field := range fields {
+		switch field.key {
+		case "description":
+			m.Description = field.value
+		case "author":
+			m.Author = field.value
+		case "license":
+			m.License = field.value
+		case "tile":
+			m.Width, m.Height = parseTile(field.value)
+		case "tags":
+			m.Tags = splitList(field.value)
+		case "param":
+			name, description, _ := strings.Cut(field.value, " ")
+			m.Params = append(m.Params, Param{name, strings.TrimSpace(description)})
+		}
+	}
+
+	models.set(m)
+}
+
+// set append or replace an existing model with the same name.
+func (models *Models) set(m Model) {
+	i, ok := models.GetModelIndex(m.Meta().Name)
 	if ok {
-		(*models)[i].Code = code
+		(*models)[i] = m
 	} else {
-		*models = append(*models, Model{name, code})
+		*models = append(*models, m)
+	}
+}
+
+// A headerField is a 'key: value' line from the model header.
+type headerField struct {
+	key, value string
+}
+
+// parseHeader reads the 'key: value' lines of the template comment
+// that starts the model code, like
+//
+//	{{- /*
+//	description: Checkerboard of squares with random shades.
+//	author: kpym
+//	license: MIT
+//	tile: 28x28
+//	tags: geometric, subtle
+//	param: size the side of the squares
+//	*/ -}}
+//
+// The 'param' key can be repeated, one for each parameter.
+// If the code do not start with a comment, no fields are provided.
+func parseHeader(code string) (fields []headerField) {
+	code = strings.TrimSpace(code)
+	for _, start := range []string{"{{- /*", "{{/*"} {
+		if strings.HasPrefix(code, start) {
+			code = strings.TrimPrefix(code, start)
+			end := strings.Index(code, "*/")
+			if end < 0 {
+				return nil
+			}
+			return parseFields(code[:end])
+		}
+	}
+
+	return nil
+}
+
+// parseFields reads the 'key: value' lines of a header or a sidecar file.
+func parseFields(s string) (fields []headerField) {
+	for _, line := range strings.Split(s, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok {
+			fields = append(fields, headerField{strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)})
+		}
 	}
+
+	return fields
 }
 
-//go:embed svgmodels/*.template.svg
+// parseTile reads tile dimensions like '90x25.98'.
+// If the value is not valid, zero dimensions are provided.
+func parseTile(s string) (width, height float64) {
+	ws, hs, _ := strings.Cut(s, "x")
+	width, errw := strconv.ParseFloat(strings.TrimSpace(ws), 64)
+	height, errh := strconv.ParseFloat(strings.TrimSpace(hs), 64)
+	if errw != nil || errh != nil {
+		return 0, 0
+	}
+
+	return width, height
+}
+
+// splitList splits a comma separated list and trim the elements.
+func splitList(s string) (list []string) {
+	for _, e := range strings.Split(s, ",") {
+		e = strings.TrimSpace(e)
+		if e != "" {
+			list = append(list, e)
+		}
+	}
+
+	return list
+}
+
+//go:embed svgmodels/*/*.template.svg svgmodels/*/*.meta
 var files embed.FS
 
-// Init the Models list with the embedded svg templates.
+// versionNumber provides the number of the version named like 'v12'.
+// If the name is not valid, -1 is provided.
+func versionNumber(name string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(name, "v"))
+	if err != nil || !strings.HasPrefix(name, "v") {
+		return -1
+	}
+
+	return n
+}
+
+// Init the Models lists with the embedded svg templates and the builtin go models.
 func init() {
-	svgdir, _ := fs.Sub(files, "svgmodels")
-	svgmodels, _ := fs.ReadDir(svgdir, ".")
-	for _, svgmodfile := range svgmodels {
-		fname := svgmodfile.Name()
-		fdata, _ := fs.ReadFile(svgdir, fname)
-		name := strings.TrimSuffix(fname, ".template.svg")
-		code := string(fdata)
-		EmbeddedModels.SetModel(name, code)
+	svgdirs, _ := fs.ReadDir(files, "svgmodels")
+	for _, dir := range svgdirs {
+		if dir.IsDir() && versionNumber(dir.Name()) >= 0 {
+			VersionNames = append(VersionNames, dir.Name())
+		}
+	}
+	sort.Slice(VersionNames, func(i, j int) bool {
+		return versionNumber(VersionNames[i]) < versionNumber(VersionNames[j])
+	})
+
+	var models Models
+	for _, version := range VersionNames {
+		// start from a copy of the previous version
+		models = append(Models(nil), models...)
+		svgdir, _ := fs.Sub(files, "svgmodels/"+version)
+		svgmodels, _ := fs.ReadDir(svgdir, ".")
+		for _, svgmodfile := range svgmodels {
+			fname := svgmodfile.Name()
+			if !strings.HasSuffix(fname, ".template.svg") {
+				continue
+			}
+			fdata, _ := fs.ReadFile(svgdir, fname)
+			name := strings.TrimSuffix(fname, ".template.svg")
+			code := string(fdata)
+			fields := parseHeader(code)
+			// the sidecar metadata completes the header
+			if meta, err := fs.ReadFile(svgdir, name+".meta"); err == nil {
+				fields = append(fields, parseFields(string(meta))...)
+			}
+			models.setTemplate(name, code, fields)
+		}
+		Versions[version] = models
+		LatestVersion = version
+	}
+	EmbeddedModels = Versions[LatestVersion]
+
+	for _, m := range builtinGoModels {
+		Register(m.version, m.meta, m.renderer)
 	}
 }
@@ -0,0 +1,181 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func TestParseHeader(t *testing.T) {
+	data := []struct {
+		code string
+		out  string
+		msg  string
+	}{
+		{"{{- /*\ntags: a, b\n*/ -}}\n<svg/>", "[{tags a, b}]", "header with one field"},
+		{"{{/* Tags : a\nno field here\nauthor: me */}}<svg/>", "[{tags a} {author me}]", "keys should be trimmed and lowercased"},
+		{"<svg/>{{/* tags: a */}}", "[]", "the comment should start the code"},
+		{"{{/* tags: a", "[]", "unterminated comment"},
+	}
+	for _, tt := range data {
+		res := fmt.Sprintf("%v", parseHeader(tt.code))
+		if res != tt.out {
+			t.Errorf(tt.msg+", got %s, want %s", res, tt.out)
+		}
+	}
+}
+
+func TestFilterExclude(t *testing.T) {
+	var models Models
+	models.SetModel("a", "{{/* tags: geometric, subtle */}}")
+	models.SetModel("b", "{{/* tags: geometric, busy */}}")
+	models.SetModel("c", "{{/* tags: organic, subtle */}}")
+
+	data := []struct {
+		models Models
+		out    string
+		msg    string
+	}{
+		{models.Filter("subtle"), "a, c", "filter one tag"},
+		{models.Filter("subtle", "geometric"), "a", "filter should keep models with all tags"},
+		{models.Filter("bingo"), "", "filter unknown tag"},
+		{models.Filter(), "a, b, c", "filter without tags"},
+		{models.Exclude("b", "bingo"), "a, c", "exclude"},
+		{models.Filter("subtle").Exclude("a"), "c", "filter and exclude"},
+	}
+	for _, tt := range data {
+		res := tt.models.ModelsString()
+		if res != tt.out {
+			t.Errorf(tt.msg+", got %s, want %s", res, tt.out)
+		}
+	}
+}
+
+func TestSetModelMetadata(t *testing.T) {
+	var models Models
+	models.SetModel("a", `{{- /*
+description: A test model.
+author: me
+license: MIT
+tile: 90x25.98
+tags: geometric
+param: nx number of columns
+param: width
+*/ -}}<svg/>`)
+
+	res := fmt.Sprintf("%+v", models[0].Meta())
+	want := "{Name:a Description:A test model. Author:me License:MIT Width:90 Height:25.98 Tags:[geometric] Params:[{Name:nx Description:number of columns} {Name:width Description:}]}"
+	if res != want {
+		t.Errorf("The metadata is not parsed as expected, got %s, want %s", res, want)
+	}
+
+	models.SetModel("a", "<svg/>")
+	if len(models) != 1 || models[0].Meta().Description != "" {
+		t.Errorf("The model should be replaced, got %+v", models)
+	}
+}
+
+func TestParseTile(t *testing.T) {
+	data := []struct {
+		in   string
+		w, h float64
+	}{
+		{"90x25.98", 90, 25.98},
+		{" 7 x 3 ", 7, 3},
+		{"7", 0, 0},
+		{"ax3", 0, 0},
+	}
+	for _, tt := range data {
+		w, h := parseTile(tt.in)
+		if w != tt.w || h != tt.h {
+			t.Errorf("parseTile(%q) got %vx%v, want %vx%v", tt.in, w, h, tt.w, tt.h)
+		}
+	}
+}
+
+func TestEmbeddedMetadata(t *testing.T) {
+	for version, models := range Versions {
+		for _, model := range models {
+			m := model.Meta()
+			if m.Description == "" || len(m.Tags) == 0 || m.Width <= 0 || m.Height <= 0 {
+				t.Errorf("The embedded %s model %s has incomplete metadata: %+v.", version, m.Name, m)
+			}
+		}
+	}
+	// the v1 metadata is in sidecar files
+	for _, model := range Versions["v1"] {
+		if m, ok := model.(TemplateModel); ok && len(parseHeader(m.Code)) > 0 {
+			t.Errorf("The v1 model %s should not have a header.", m.Name)
+		}
+	}
+}
+
+// A testRenderer renders a circle with random radius.
+type testRenderer struct{}
+
+func (testRenderer) Render(w io.Writer, r *rand.Rand, data Data) error {
+	_, err := fmt.Fprintf(w, `<svg><circle id="%scircle" r="%d" fill="%s"/></svg>`, data.ID, r.Intn(10), data.Dark)
+	return err
+}
+
+func TestRegister(t *testing.T) {
+	saved, savedEmbedded := make(map[string]Models), EmbeddedModels
+	for v, models := range Versions {
+		saved[v] = models
+	}
+	defer func() { Versions, EmbeddedModels = saved, savedEmbedded }()
+
+	first := VersionNames[0]
+	if err := Register(first, Metadata{Name: "test-circle", Tags: []string{"test"}}, testRenderer{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range VersionNames {
+		models := Versions[v].Filter("test")
+		if len(models) != 1 || len(Versions[v]) != len(saved[v])+1 {
+			t.Fatalf("The go model should be added to the version %s, got %s.", v, models.ModelsString())
+		}
+		if _, ok := models[0].(GoModel); !ok {
+			t.Errorf("The registered model should be a go model, got %T.", models[0])
+		}
+	}
+	if len(EmbeddedModels) != len(savedEmbedded)+1 {
+		t.Error("The go model should be added to the embedded models.")
+	}
+	if len(saved[first].Filter("test")) != 0 {
+		t.Error("The registration should not modify the previous models lists.")
+	}
+
+	if err := Register("v0", Metadata{Name: "test"}, testRenderer{}); err == nil {
+		t.Error("The registration to an unknown version should fail.")
+	}
+	if err := Register(first, Metadata{}, testRenderer{}); err == nil {
+		t.Error("The registration without name should fail.")
+	}
+}
+
+func TestDataFloat(t *testing.T) {
+	var limits []interface{}
+	d := Data{Param: func(name string, value interface{}, l ...interface{}) interface{} {
+		limits = l
+		switch name {
+		case "size":
+			return 12.5
+		case "n":
+			return 7
+		}
+		return value
+	}}
+	if d.Float("size", 3, 1, 20) != 12.5 || d.Float("other", 3, 1, 20) != 3 {
+		t.Errorf("The parameters are not read as expected: %v, %v.", d.Float("size", 3, 1, 20), d.Float("other", 3, 1, 20))
+	}
+	if len(limits) != 2 || limits[0] != 1.0 || limits[1] != 20.0 {
+		t.Errorf("The limits should be passed to Param, got %v.", limits)
+	}
+	if d.Int("n", 3, 1, 20) != 7 || d.Int("size", 3, 1, 20) != 3 {
+		t.Errorf("The integer parameters are not read as expected: %v, %v.", d.Int("n", 3, 1, 20), d.Int("size", 3, 1, 20))
+	}
+	if (Data{}).Float("size", 3, 1, 20) != 3 || (Data{}).Int("n", 3, 1, 20) != 3 {
+		t.Error("The default value should be used without Param.")
+	}
+}
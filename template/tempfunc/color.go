@@ -0,0 +1,117 @@
+// Package tempfunc provide template functions.
+// The description is in random.go.
+package tempfunc
+
+import (
+	"fmt"
+	"math"
+	"text/template"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// ColorFunctions provides template functions to manipulate hex colors like "#a17" or "#aa1177".
+// The color is the last parameter, so the functions can be used in pipelines like
+// {{ .Color | lighten 0.2 }}. If the color is not valid it is provided unchanged.
+func ColorFunctions() template.FuncMap {
+	return map[string]interface{}{
+		"lighten":   lighten,
+		"darken":    darken,
+		"saturate":  saturate,
+		"rotatehue": rotateHue,
+		"mix":       mix,
+		"alpha":     alpha,
+		"contrast":  contrast,
+	}
+}
+
+// modifyHsl parses the hex color, applies f to its HSL representation
+// and provides the resulting hex color.
+// The saturation and the lightness are clamped to [0,1].
+func modifyHsl(hex interface{}, f func(h, s, l float64) (float64, float64, float64)) string {
+	str := fmt.Sprint(hex)
+	c, err := colorful.Hex(str)
+	if err != nil {
+		return str
+	}
+	h, s, l := f(c.Hsl())
+
+	return colorful.Hsl(h, clamp01(s), clamp01(l)).Clamped().Hex()
+}
+
+// clamp01 restricts x to [0,1].
+func clamp01(x float64) float64 {
+	return math.Min(math.Max(x, 0), 1)
+}
+
+// lighten increases the HSL lightness of the color by amount (in [0,1]).
+func lighten(amount, hex interface{}) string {
+	a := toFloat64(amount)
+	return modifyHsl(hex, func(h, s, l float64) (float64, float64, float64) { return h, s, l + a })
+}
+
+// darken decreases the HSL lightness of the color by amount (in [0,1]).
+func darken(amount, hex interface{}) string {
+	a := toFloat64(amount)
+	return modifyHsl(hex, func(h, s, l float64) (float64, float64, float64) { return h, s, l - a })
+}
+
+// saturate increases the HSL saturation of the color by amount (in [0,1]).
+// Use a negative amount to desaturate.
+func saturate(amount, hex interface{}) string {
+	a := toFloat64(amount)
+	return modifyHsl(hex, func(h, s, l float64) (float64, float64, float64) { return h, s + a, l })
+}
+
+// rotateHue rotates the HSL hue of the color by the angle in degree.
+func rotateHue(angle, hex interface{}) string {
+	a := toFloat64(angle)
+	return modifyHsl(hex, func(h, s, l float64) (float64, float64, float64) { return math.Mod(h+a+360, 360), s, l })
+}
+
+// mix blends the color with the other one, t (in [0,1]) being the proportion of the other color.
+func mix(other, t, hex interface{}) string {
+	str := fmt.Sprint(hex)
+	c1, err1 := colorful.Hex(str)
+	c2, err2 := colorful.Hex(fmt.Sprint(other))
+	if err1 != nil || err2 != nil {
+		return str
+	}
+
+	return c1.BlendRgb(c2, clamp01(toFloat64(t))).Clamped().Hex()
+}
+
+// alpha provides the color with the opacity a (in [0,1]) as "rgba(r,g,b,a)".
+func alpha(a, hex interface{}) string {
+	str := fmt.Sprint(hex)
+	c, err := colorful.Hex(str)
+	if err != nil {
+		return str
+	}
+	r, g, b := c.RGB255()
+
+	return fmt.Sprintf("rgba(%d,%d,%d,%s)", r, g, b, round(2, clamp01(toFloat64(a))))
+}
+
+// contrast provides "#000000" or "#ffffff", the one that is the most readable
+// on the color (see Contrast).
+// If the color is not valid, black is provided.
+func contrast(hex interface{}) string {
+	c, err := colorful.Hex(fmt.Sprint(hex))
+	if err != nil {
+		return "#000000"
+	}
+
+	return Contrast(c)
+}
+
+// Contrast provides "#000000" or "#ffffff", the one with the best contrast
+// against the color c, depending on its relative luminance.
+func Contrast(c colorful.Color) string {
+	r, g, b := c.Clamped().LinearRgb()
+	if 0.2126*r+0.7152*g+0.0722*b > 0.179 {
+		return "#000000"
+	}
+
+	return "#ffffff"
+}
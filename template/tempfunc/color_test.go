@@ -0,0 +1,68 @@
+package tempfunc
+
+import (
+	"os"
+	"testing"
+	"text/template"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+func TestColorFunctions(t *testing.T) {
+	data := []struct {
+		res  string
+		want string
+		msg  string
+	}{
+		{lighten(0.5, "#000"), "#808080", "lighten black by 0.5"},
+		{lighten(2, "#123456"), "#ffffff", "the lightness should be clamped"},
+		{darken(0.5, "#fff"), "#808080", "darken white by 0.5"},
+		{darken("0.25", "#ff0000"), "#800000", "the amount can be a string"},
+		{saturate(-1, "#ff0000"), "#808080", "desaturate red"},
+		{saturate(0.5, "#bf4040"), "#ff0000", "saturate"},
+		{rotateHue(120, "#ff0000"), "#00ff00", "rotate red by 120°"},
+		{rotateHue(-120, "#ff0000"), "#0000ff", "rotate red by -120°"},
+		{mix("#fff", 0.5, "#000"), "#808080", "mix black and white"},
+		{mix("#fff", 0, "#a17"), "#aa1177", "mix with 0 should keep the color"},
+		{alpha(0.5, "#a17"), "rgba(170,17,119,0.5)", "alpha"},
+		{alpha(7, "#a17"), "rgba(170,17,119,1)", "the alpha should be clamped"},
+		{contrast("#fff"), "#000000", "contrast of white"},
+		{contrast("#123456"), "#ffffff", "contrast of dark blue"},
+		{lighten(0.5, "bingo"), "bingo", "invalid color should be unchanged"},
+		{mix("bingo", 0.5, "#000"), "#000", "invalid color should be unchanged"},
+	}
+	for _, tt := range data {
+		if tt.res != tt.want {
+			t.Errorf(tt.msg+", got %s, want %s", tt.res, tt.want)
+		}
+	}
+}
+
+// The color functions take the color as last parameter to be used in pipelines.
+func ExampleColorFunctions() {
+	const hello string = `Hello, {{ "#a17" | lighten 0.1 }}, {{ "#a17" | mix "#fff" 0.5 }} and {{ "#a17" | contrast }} !`
+	// compile and execute the template (without error check, very bad idea!)
+	t, _ := template.New("hi").Funcs(ColorFunctions()).Parse(hello)
+	t.Execute(os.Stdout, nil)
+	// Output:
+	// Hello, #d81697, #d488bb and #ffffff !
+}
+
+func TestContrast(t *testing.T) {
+	data := []struct {
+		in, out string
+	}{
+		{"#000000", "#ffffff"},
+		{"#ffffff", "#000000"},
+		{"#ffff00", "#000000"},
+		{"#0000ff", "#ffffff"},
+		{"#4f8a3b", "#000000"},
+		{"#3c2a6e", "#ffffff"},
+	}
+	for _, tt := range data {
+		c, _ := colorful.Hex(tt.in)
+		if res := Contrast(c); res != tt.out {
+			t.Errorf("The contrast color of %s should be %s, got %s.", tt.in, tt.out, res)
+		}
+	}
+}
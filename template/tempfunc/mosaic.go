@@ -0,0 +1,72 @@
+// Package tempfunc provide template functions.
+// The description is in random.go.
+package tempfunc
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/kpym/svgpattern/template/geometry"
+)
+
+// A Cell is a polygon of a seamless mosaic (see the `voronoi` and `delaunay` functions).
+// (X,Y) is the center of the cell inside the pattern, Points are the polygon points "x1,y1 x2,y2 ..."
+// and Offsets are the translations of the copies needed to tile seamlessly (see wrap).
+type Cell struct {
+	X, Y    float64
+	Points  string
+	Offsets []Offset
+}
+
+// newCell provides the cell of the polygon with the provided center in the width×height pattern.
+func newCell(center geometry.Point, polygon []geometry.Point, width, height float64) Cell {
+	points := make([]string, len(polygon))
+	extent := 0.0
+	for i, p := range polygon {
+		points[i] = point(p.X, p.Y)
+		extent = math.Max(extent, math.Hypot(p.X-center.X, p.Y-center.Y))
+	}
+
+	return Cell{center.X, center.Y, strings.Join(points, " "), wrap(width, height, extent, center.X, center.Y)}
+}
+
+// randomPoints provides n random points in the width×height pattern.
+func randomPoints(r *rand.Rand, n int, width, height float64) []geometry.Point {
+	points := make([]geometry.Point, n)
+	for i := range points {
+		points[i] = geometry.Point{X: r.Float64() * width, Y: r.Float64() * height}
+	}
+
+	return points
+}
+
+// randomVoronoi provides the Voronoi cells of n random points in the width×height pattern.
+func randomVoronoi(r *rand.Rand, n, width, height interface{}) []Cell {
+	num, w, h := int(toFloat64(n)), toFloat64(width), toFloat64(height)
+	if num < 1 || w <= 0 || h <= 0 {
+		return nil
+	}
+	points := randomPoints(r, num, w, h)
+	cells := make([]Cell, 0, num)
+	for i, polygon := range geometry.TorusVoronoi(points, w, h) {
+		cells = append(cells, newCell(points[i], polygon, w, h))
+	}
+
+	return cells
+}
+
+// randomDelaunay provides the Delaunay triangles of n random points in the width×height pattern.
+func randomDelaunay(r *rand.Rand, n, width, height interface{}) []Cell {
+	num, w, h := int(toFloat64(n)), toFloat64(width), toFloat64(height)
+	if num < 1 || w <= 0 || h <= 0 {
+		return nil
+	}
+	triangles := geometry.TorusDelaunay(randomPoints(r, num, w, h), w, h)
+	cells := make([]Cell, 0, len(triangles))
+	for _, t := range triangles {
+		cells = append(cells, newCell(t.Centroid(), t[:], w, h))
+	}
+
+	return cells
+}
@@ -0,0 +1,58 @@
+package tempfunc
+
+import (
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestRandomMosaics(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	cells := randomVoronoi(r, 30, 200, 100)
+	if len(cells) != 30 {
+		t.Fatalf("there should be 30 voronoi cells, got %d", len(cells))
+	}
+	triangles := randomDelaunay(r, 30, 200, 100)
+	if len(triangles) != 60 {
+		t.Fatalf("there should be 60 delaunay triangles, got %d", len(triangles))
+	}
+	copies := 0
+	for _, c := range append(cells, triangles...) {
+		if c.X < 0 || c.X >= 200 || c.Y < 0 || c.Y >= 100 {
+			t.Errorf("the cell center (%f,%f) should be inside the pattern", c.X, c.Y)
+		}
+		if len(c.Offsets) == 0 || c.Offsets[0].DX != 0 || c.Offsets[0].DY != 0 {
+			t.Errorf("the first offset should be the cell itself, got %v", c.Offsets)
+		}
+		copies += len(c.Offsets) - 1
+	}
+	if copies == 0 {
+		t.Error("some cells should cross the pattern bounds")
+	}
+	if strings.Count(triangles[0].Points, ",") != 3 {
+		t.Errorf("a triangle should have 3 points, got %s", triangles[0].Points)
+	}
+
+	if randomVoronoi(r, 0, 200, 100) != nil || randomDelaunay(r, 10, 0, 100) != nil {
+		t.Error("there should be no cells without points or pattern")
+	}
+}
+
+// The function `voronoi` provides the cells of random points,
+// with the offsets of their copies for a seamless pattern.
+func ExampleRandomFunctions_voronoi() {
+	const hello string = `{{ range $c := voronoi 3 100 100 }}{{ range $i, $o := $c.Offsets }}{{ if $i }} {{ end }}({{ $o.DX | round 2 }},{{ $o.DY | round 2 }}){{ end }}
+{{ end }}`
+	// the random generator use 42 as seed
+	rf := RandomFunctions(42)
+	uf := UtilFunctions()
+	// compile and execute the template (without error check, very bad idea!)
+	t, _ := template.New("hi").Funcs(rf).Funcs(uf).Parse(hello)
+	t.Execute(os.Stdout, nil)
+	// Output:
+	// (0,0) (100,0) (0,100) (100,100)
+	// (0,0) (-100,0) (0,100) (-100,100)
+	// (0,0) (100,0) (0,100) (100,100)
+}
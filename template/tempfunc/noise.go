@@ -0,0 +1,301 @@
+// Package tempfunc provide template functions.
+// The description is in random.go.
+package tempfunc
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Noise is a seeded 2-D coherent noise generator:
+// close points have close values, far points have independent values.
+// All noise values are in [0,1].
+//
+// The Perlin and Fractal noises can be periodic (with integer period),
+// in this case the noise on the square [0,period]² tiles seamlessly.
+type Noise struct {
+	perm [512]int
+}
+
+// NewNoise provides a noise generator from the seed.
+// The seed is not used directly, so the noise is independent
+// from a random generator with the same seed.
+func NewNoise(seed int64) *Noise {
+	n := new(Noise)
+	p := rand.New(rand.NewSource(seed ^ 0x5deece66d)).Perm(256)
+	for i := range n.perm {
+		n.perm[i] = p[i%256]
+	}
+
+	return n
+}
+
+// hash provides a pseudo random integer in [0,255] for the lattice point (ix, iy).
+func (n *Noise) hash(ix, iy int) int {
+	return n.perm[n.perm[ix&255]+(iy&255)]
+}
+
+// smooth is the smootherstep interpolation weight of t in [0,1].
+func smooth(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// Value provides the value noise at the point (x, y): random values
+// on the integer lattice smoothly interpolated between the lattice points.
+func (n *Noise) Value(x, y float64) float64 {
+	fx, fy := math.Floor(x), math.Floor(y)
+	ix, iy := int(fx), int(fy)
+	// smoothstep interpolation weights
+	tx, ty := x-fx, y-fy
+	tx, ty = tx*tx*(3-2*tx), ty*ty*(3-2*ty)
+
+	v00 := float64(n.hash(ix, iy)) / 255
+	v10 := float64(n.hash(ix+1, iy)) / 255
+	v01 := float64(n.hash(ix, iy+1)) / 255
+	v11 := float64(n.hash(ix+1, iy+1)) / 255
+	top := v00 + tx*(v10-v00)
+	bottom := v01 + tx*(v11-v01)
+
+	return top + ty*(bottom-top)
+}
+
+// wrapIndex provides i modulo period (in [0,period)), or i if period is not positive.
+func wrapIndex(i, period int) int {
+	if period <= 0 {
+		return i
+	}
+
+	return ((i % period) + period) % period
+}
+
+// gradient provides the dot product of the pseudo random unit gradient
+// of the lattice point (ix, iy) with the vector (dx, dy).
+func (n *Noise) gradient(ix, iy int, dx, dy float64) float64 {
+	a := float64(n.hash(ix, iy)) * 2 * math.Pi / 256
+	return math.Cos(a)*dx + math.Sin(a)*dy
+}
+
+// Perlin provides the gradient (Perlin) noise at the point (x, y).
+// If period is positive the noise is periodic in x and y with this period.
+func (n *Noise) Perlin(x, y float64, period int) float64 {
+	fx, fy := math.Floor(x), math.Floor(y)
+	ix, iy := int(fx), int(fy)
+	dx, dy := x-fx, y-fy
+	x0, x1 := wrapIndex(ix, period), wrapIndex(ix+1, period)
+	y0, y1 := wrapIndex(iy, period), wrapIndex(iy+1, period)
+
+	g00 := n.gradient(x0, y0, dx, dy)
+	g10 := n.gradient(x1, y0, dx-1, dy)
+	g01 := n.gradient(x0, y1, dx, dy-1)
+	g11 := n.gradient(x1, y1, dx-1, dy-1)
+	tx, ty := smooth(dx), smooth(dy)
+	top := g00 + tx*(g10-g00)
+	bottom := g01 + tx*(g11-g01)
+
+	// the 2-D Perlin noise is in [-√½,√½]
+	return clamp01(0.5 + (top+ty*(bottom-top))/math.Sqrt2)
+}
+
+// Fractal provides the fractal (fBm) sum of octaves Perlin noises at the point (x, y),
+// each octave having double frequency and half amplitude of the previous one.
+// If period is positive the noise is periodic in x and y with this period.
+func (n *Noise) Fractal(x, y float64, period, octaves int) float64 {
+	if octaves < 1 {
+		octaves = 1
+	}
+	sum, amplitude, total := 0.0, 1.0, 0.0
+	for o := 0; o < octaves; o++ {
+		sum += amplitude * (n.Perlin(x, y, period) - 0.5)
+		total += amplitude
+		x, y, period = 2*x, 2*y, 2*period
+		amplitude /= 2
+		// shift the octaves to avoid the alignment of the lattices
+		x, y = x+float64(period*o), y+float64(period*o)
+	}
+
+	return clamp01(0.5 + sum/total)
+}
+
+// Isolines provides the svg path data of the level line of the fractal noise
+// on the square [0,size]², sampled on a grid of cells×cells.
+// The noise is periodic with the provided period on the square,
+// so the lines tile seamlessly. The lines are computed by marching squares.
+func (n *Noise) Isolines(period, octaves, cells int, size, level float64) string {
+	if cells < 1 || period < 1 {
+		return ""
+	}
+	step := float64(period) / float64(cells)
+	// the field values on the (cells+1)×(cells+1) grid points
+	v := make([][]float64, cells+1)
+	for i := range v {
+		v[i] = make([]float64, cells+1)
+		for j := range v[i] {
+			v[i][j] = n.Fractal(float64(wrapIndex(i, cells))*step, float64(wrapIndex(j, cells))*step, period, octaves) - level
+		}
+	}
+
+	l := newLines(cells, size)
+	for i := 0; i < cells; i++ {
+		for j := 0; j < cells; j++ {
+			l.cell(i, j, v[i][j], v[i+1][j], v[i+1][j+1], v[i][j+1])
+		}
+	}
+
+	return l.path()
+}
+
+// An edge is a grid edge from the point (i, j), horizontal or vertical.
+type edge struct {
+	i, j       int
+	horizontal bool
+}
+
+// lines collects the isoline segments and their crossing points on the grid edges.
+type lines struct {
+	cells    int
+	size     float64
+	points   map[edge][2]float64
+	segments map[edge][]edge
+}
+
+// newLines prepares the isolines on the cells×cells grid of the square [0,size]².
+func newLines(cells int, size float64) *lines {
+	return &lines{cells, size, make(map[edge][2]float64), make(map[edge][]edge)}
+}
+
+// cross saves the point where the isoline crosses the edge from (i,j),
+// the values at the edge ends being a and b.
+func (l *lines) cross(e edge, a, b float64) edge {
+	t := a / (a - b)
+	x, y := float64(e.i), float64(e.j)
+	if e.horizontal {
+		x += t
+	} else {
+		y += t
+	}
+	c := l.size / float64(l.cells)
+	l.points[e] = [2]float64{x * c, y * c}
+
+	return e
+}
+
+// segment saves the isoline segment between two edges.
+func (l *lines) segment(a, b edge) {
+	l.segments[a] = append(l.segments[a], b)
+	l.segments[b] = append(l.segments[b], a)
+}
+
+// cell adds the segments of the cell (i,j) with corner values
+// v00 (top-left), v10 (top-right), v11 (bottom-right) and v01 (bottom-left).
+func (l *lines) cell(i, j int, v00, v10, v11, v01 float64) {
+	var crossed []edge
+	if (v00 < 0) != (v10 < 0) {
+		crossed = append(crossed, l.cross(edge{i, j, true}, v00, v10))
+	}
+	if (v10 < 0) != (v11 < 0) {
+		crossed = append(crossed, l.cross(edge{i + 1, j, false}, v10, v11))
+	}
+	if (v01 < 0) != (v11 < 0) {
+		crossed = append(crossed, l.cross(edge{i, j + 1, true}, v01, v11))
+	}
+	if (v00 < 0) != (v01 < 0) {
+		crossed = append(crossed, l.cross(edge{i, j, false}, v00, v01))
+	}
+
+	switch len(crossed) {
+	case 2:
+		l.segment(crossed[0], crossed[1])
+	case 4:
+		// saddle: the center value decides which corners are connected
+		if ((v00+v10+v11+v01)/4 < 0) == (v00 < 0) {
+			l.segment(crossed[0], crossed[1])
+			l.segment(crossed[2], crossed[3])
+		} else {
+			l.segment(crossed[0], crossed[3])
+			l.segment(crossed[1], crossed[2])
+		}
+	}
+}
+
+// path chains the segments in polylines and provides the svg path data.
+func (l *lines) path() string {
+	// sort the edges to be deterministic
+	edges := make([]edge, 0, len(l.segments))
+	for e := range l.segments {
+		edges = append(edges, e)
+	}
+	sortEdges(edges)
+
+	var b strings.Builder
+	visited := make(map[[2]edge]bool)
+	visit := func(a, c edge) bool {
+		if visited[[2]edge{a, c}] {
+			return false
+		}
+		visited[[2]edge{a, c}], visited[[2]edge{c, a}] = true, true
+		return true
+	}
+	// start first from the open line ends (one segment), then from the loops
+	for _, open := range []bool{true, false} {
+		for _, start := range edges {
+			if open != (len(l.segments[start]) == 1) {
+				continue
+			}
+			current, first := start, true
+			for {
+				next, found := edge{}, false
+				for _, e := range l.segments[current] {
+					if visit(current, e) {
+						next, found = e, true
+						break
+					}
+				}
+				if !found {
+					break
+				}
+				if first {
+					p := l.points[current]
+					fmt.Fprintf(&b, "M%s ", point(p[0], p[1]))
+					first = false
+				}
+				p := l.points[next]
+				fmt.Fprintf(&b, "L%s ", point(p[0], p[1]))
+				current = next
+			}
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// sortEdges sorts the edges by position.
+func sortEdges(edges []edge) {
+	sort.Slice(edges, func(a, b int) bool {
+		ea, eb := edges[a], edges[b]
+		if ea.j != eb.j {
+			return ea.j < eb.j
+		}
+		if ea.i != eb.i {
+			return ea.i < eb.i
+		}
+		return ea.horizontal && !eb.horizontal
+	})
+}
+
+// noisePerlin is the `perlin` template function.
+func noisePerlin(n *Noise, period, x, y interface{}) float64 {
+	return n.Perlin(toFloat64(x), toFloat64(y), int(toFloat64(period)))
+}
+
+// noiseFractal is the `fbm` template function.
+func noiseFractal(n *Noise, octaves, period, x, y interface{}) float64 {
+	return n.Fractal(toFloat64(x), toFloat64(y), int(toFloat64(period)), int(toFloat64(octaves)))
+}
+
+// noiseIsolines is the `isolines` template function.
+func noiseIsolines(n *Noise, octaves, period, cells, size, level interface{}) string {
+	return n.Isolines(int(toFloat64(period)), int(toFloat64(octaves)), int(toFloat64(cells)), toFloat64(size), toFloat64(level))
+}
@@ -0,0 +1,80 @@
+package tempfunc
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestNoisePeriodic(t *testing.T) {
+	n := NewNoise(42)
+	for i := 0; i < 100; i++ {
+		x, y := float64(i)*0.37, float64(i)*0.73
+		p, f := n.Perlin(x, y, 3), n.Fractal(x, y, 3, 4)
+		if p < 0 || p > 1 || f < 0 || f > 1 {
+			t.Errorf("noise should be in [0,1], got %f and %f", p, f)
+		}
+		if d := math.Abs(p - n.Perlin(x+3, y-6, 3)); d > 1e-9 {
+			t.Errorf("perlin should be periodic, got a difference of %f at (%f,%f)", d, x, y)
+		}
+		if d := math.Abs(f - n.Fractal(x-3, y+3, 3, 4)); d > 1e-9 {
+			t.Errorf("fbm should be periodic, got a difference of %f at (%f,%f)", d, x, y)
+		}
+		if d := math.Abs(f - n.Fractal(x+0.001, y, 3, 4)); d > 0.02 {
+			t.Errorf("fbm should be continuous, got a jump of %f at (%f,%f)", d, x, y)
+		}
+	}
+	if NewNoise(42).Perlin(1.5, 2.5, 0) == NewNoise(43).Perlin(1.5, 2.5, 0) {
+		t.Error("perlin should depend on the seed")
+	}
+}
+
+func TestNoiseIsolines(t *testing.T) {
+	n := NewNoise(42)
+	d := n.Isolines(2, 3, 32, 100, 0.5)
+	if !strings.HasPrefix(d, "M") || !strings.Contains(d, "L") {
+		t.Fatalf("the isolines should be a path, got: %s", d)
+	}
+	if d != NewNoise(42).Isolines(2, 3, 32, 100, 0.5) {
+		t.Error("the isolines should be reproducible")
+	}
+	// the lines that leave the square at the left enter it at the right
+	left, right := make(map[string]bool), make(map[string]bool)
+	for _, p := range strings.Fields(strings.NewReplacer("M", "", "L", "").Replace(d)) {
+		xy := strings.Split(p, ",")
+		if xy[1] == "0" || xy[1] == "100" {
+			continue // the corners
+		}
+		switch xy[0] {
+		case "0":
+			left[xy[1]] = true
+		case "100":
+			right[xy[1]] = true
+		}
+	}
+	if len(left) == 0 || len(left) != len(right) {
+		t.Errorf("the isolines should be seamless, got %v at the left and %v at the right", left, right)
+	}
+	for y := range left {
+		if !right[y] {
+			t.Errorf("the isoline at the left (0,%s) should continue at the right", y)
+		}
+	}
+	if n.Isolines(2, 3, 32, 100, 2) != "" {
+		t.Error("there should be no isolines outside [0,1]")
+	}
+}
+
+// The function `fbm` provides a periodic fractal noise in [0,1].
+func ExampleRandomFunctions_fbm() {
+	const hello string = `{{ $a := fbm 3 2 0.3 0.6 }}{{ $b := fbm 3 2 2.3 0.6 }}{{ eq $a $b }}`
+	// the noise use 42 as seed
+	rf := RandomFunctions(42)
+	// compile and execute the template (without error check, very bad idea!)
+	t, _ := template.New("hi").Funcs(rf).Parse(hello)
+	t.Execute(os.Stdout, nil)
+	// Output:
+	// true
+}
@@ -7,16 +7,40 @@ package tempfunc
 import (
 	"math"
 	"math/rand"
+	"reflect"
 	"text/template"
 )
 
 // RandomFunctions provides template functions for random generation/selection.
+// The noise functions (`noise`, `perlin`, `fbm` and `isolines`, see noise.go)
+// do not use the random sequence of the other functions,
+// so calling them do not modify their values.
 func RandomFunctions(seed int64) template.FuncMap {
 	r := rand.New(rand.NewSource(seed))
+	n := NewNoise(seed)
 	return map[string]interface{}{
-		"randf": func(min interface{}, max interface{}) float64 { return randomFloatMinMax(r, min, max) },
-		"randi": func(min interface{}, max interface{}) int { return randomIntMinMax(r, min, max) },
-		"pick":  func(values ...interface{}) interface{} { return randomPick(r, values) },
+		"randf":    func(min interface{}, max interface{}) float64 { return randomFloatMinMax(r, min, max) },
+		"randi":    func(min interface{}, max interface{}) int { return randomIntMinMax(r, min, max) },
+		"pick":     func(values ...interface{}) interface{} { return randomPick(r, values) },
+		"randn":    func(mean interface{}, sd interface{}) float64 { return randomNormal(r, mean, sd) },
+		"weighted": func(pairs ...interface{}) interface{} { return randomWeighted(r, pairs) },
+		"shuffle":  func(values ...interface{}) []interface{} { return randomShuffle(r, values) },
+		"sample":   func(k interface{}, values ...interface{}) []interface{} { return randomSample(r, k, values) },
+		"chance":   func(p interface{}) bool { return r.Float64() < toFloat64(p) },
+		"voronoi": func(n interface{}, width interface{}, height interface{}) []Cell {
+			return randomVoronoi(r, n, width, height)
+		},
+		"delaunay": func(n interface{}, width interface{}, height interface{}) []Cell {
+			return randomDelaunay(r, n, width, height)
+		},
+		"noise":  func(x interface{}, y interface{}) float64 { return n.Value(toFloat64(x), toFloat64(y)) },
+		"perlin": func(period interface{}, x interface{}, y interface{}) float64 { return noisePerlin(n, period, x, y) },
+		"fbm": func(octaves interface{}, period interface{}, x interface{}, y interface{}) float64 {
+			return noiseFractal(n, octaves, period, x, y)
+		},
+		"isolines": func(octaves interface{}, period interface{}, cells interface{}, size interface{}, level interface{}) string {
+			return noiseIsolines(n, octaves, period, cells, size, level)
+		},
 	}
 }
 
@@ -42,3 +66,72 @@ func randomPick(r *rand.Rand, values []interface{}) interface{} {
 
 	return values[r.Intn(n)]
 }
+
+func randomNormal(r *rand.Rand, mean interface{}, sd interface{}) float64 {
+	return toFloat64(mean) + r.NormFloat64()*toFloat64(sd)
+}
+
+// randomWeighted picks a value from the pairs (value, weight) with probability
+// proportional to its weight. The negative weights are considered zero.
+// If all weights are zero, nil is provided.
+func randomWeighted(r *rand.Rand, pairs []interface{}) interface{} {
+	total := 0.0
+	for i := 1; i < len(pairs); i += 2 {
+		total += math.Max(toFloat64(pairs[i]), 0)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	x := r.Float64() * total
+	for i := 1; i < len(pairs); i += 2 {
+		x -= math.Max(toFloat64(pairs[i]), 0)
+		if x < 0 {
+			return pairs[i-1]
+		}
+	}
+
+	return pairs[len(pairs)-len(pairs)%2-2]
+}
+
+// randomShuffle provides the values in random order.
+// The values can be provided as a single list (like the result of `upto`).
+func randomShuffle(r *rand.Rand, values []interface{}) []interface{} {
+	s := append([]interface{}(nil), flatten(values)...)
+	r.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+
+	return s
+}
+
+// randomSample provides k distinct elements of the values (in random order).
+// If k is larger than the number of values, all values are provided.
+// The values can be provided as a single list (like the result of `upto`).
+func randomSample(r *rand.Rand, k interface{}, values []interface{}) []interface{} {
+	s := randomShuffle(r, values)
+	n := int(toFloat64(k))
+	if n < 0 {
+		n = 0
+	}
+	if n > len(s) {
+		n = len(s)
+	}
+
+	return s[:n]
+}
+
+// flatten converts a single list parameter to the list of its elements.
+func flatten(values []interface{}) []interface{} {
+	if len(values) != 1 {
+		return values
+	}
+	v := reflect.ValueOf(values[0])
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return values
+	}
+	list := make([]interface{}, v.Len())
+	for i := range list {
+		list[i] = v.Index(i).Interface()
+	}
+
+	return list
+}
@@ -3,6 +3,7 @@ package tempfunc
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"os"
 	"testing"
 	"text/template"
@@ -187,3 +188,89 @@ func ExampleRandomFunctions_pick() {
 	// Output:
 	// Hello, 7!
 }
+
+func TestRandomDistributions(t *testing.T) {
+	rf := RandomFunctions(42)
+	randn := rf["randn"].(func(mean interface{}, sd interface{}) float64)
+	weighted := rf["weighted"].(func(pairs ...interface{}) interface{})
+	shuffle := rf["shuffle"].(func(values ...interface{}) []interface{})
+	sample := rf["sample"].(func(k interface{}, values ...interface{}) []interface{})
+	chance := rf["chance"].(func(p interface{}) bool)
+
+	if res := randn(7, 0); res != 7 {
+		t.Errorf("randn with zero deviation should be the mean, got %v", res)
+	}
+	for i := 0; i < 100; i++ {
+		if res := weighted("a", 0, "b", 2, "c", -1); res != "b" {
+			t.Errorf("weighted should pick only the values with positive weight, got %v", res)
+		}
+		if chance(0) || !chance(1) {
+			t.Error("chance 0 should be false and chance 1 should be true")
+		}
+	}
+	if res := weighted("a", 0, "b"); res != nil {
+		t.Errorf("weighted without positive weight should be nil, got %v", res)
+	}
+	if res := fmt.Sprint(len(shuffle([]int{1, 2, 3, 4}))); res != "4" {
+		t.Errorf("shuffle of a list should keep all elements, got %s", res)
+	}
+	sum := 0
+	for _, v := range shuffle(1, 2, 3, 4) {
+		sum += v.(int)
+	}
+	if sum != 10 {
+		t.Errorf("shuffle should be a permutation, got sum %d", sum)
+	}
+	if res := sample(2, "a", "b", "c"); len(res) != 2 || res[0] == res[1] {
+		t.Errorf("sample 2 should provide two distinct elements, got %v", res)
+	}
+	if res := sample(7, []string{"a", "b"}); len(res) != 2 {
+		t.Errorf("sample larger than the list should provide all elements, got %v", res)
+	}
+
+	// same or not ?
+	rand1 := RandomFunctions(42)["randn"].(func(mean interface{}, sd interface{}) float64)(0, 1)
+	rand2 := RandomFunctions(42)["randn"].(func(mean interface{}, sd interface{}) float64)(0, 1)
+	if rand1 != rand2 {
+		t.Errorf("the two results must be the same : %f and %f", rand1, rand2)
+	}
+}
+
+func TestRandomNoise(t *testing.T) {
+	rf := RandomFunctions(42)
+	noise := rf["noise"].(func(x interface{}, y interface{}) float64)
+	for i := 0; i < 100; i++ {
+		x, y := float64(i)*0.37, float64(i)*0.73
+		n := noise(x, y)
+		if n < 0 || n > 1 {
+			t.Errorf("noise should be in [0,1], got %f", n)
+		}
+		if d := math.Abs(n - noise(x+0.001, y)); d > 0.01 {
+			t.Errorf("noise should be continuous, got a jump of %f at (%f,%f)", d, x, y)
+		}
+	}
+	if noise(1.5, 2.5) != RandomFunctions(42)["noise"].(func(x interface{}, y interface{}) float64)(1.5, 2.5) {
+		t.Error("noise should be reproducible")
+	}
+
+	// the noise do not consume the random sequence
+	rand1 := rf["randf"].(func(min interface{}, max interface{}) float64)(1, 2)
+	rand2 := RandomFunctions(42)["randf"].(func(min interface{}, max interface{}) float64)(1, 2)
+	if rand1 != rand2 {
+		t.Errorf("noise should not modify the random sequence : %f and %f", rand1, rand2)
+	}
+}
+
+// The function `weighted` picks a value from (value, weight) pairs,
+// `chance` is true with the provided probability,
+// and `sample` picks distinct elements from a list.
+func ExampleRandomFunctions_distributions() {
+	const hello string = `Hello, {{ weighted "apple" 1 "banana" 0 }}{{ if chance 1 }} and {{ sample 2 "kiwi" "kiwi" | len }} kiwis{{ end }} !`
+	// the random generator use 42 as seed
+	rf := RandomFunctions(42)
+	// compile and execute the template (without error check, very bad idea!)
+	t, _ := template.New("hi").Funcs(rf).Parse(hello)
+	t.Execute(os.Stdout, nil)
+	// Output:
+	// Hello, apple and 2 kiwis !
+}
@@ -4,6 +4,7 @@ package tempfunc
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"text/template"
@@ -12,20 +13,36 @@ import (
 // UtilFunctions provides a set of utility template functions.
 func UtilFunctions() template.FuncMap {
 	return map[string]interface{}{
-		"float":  toFloat64,
-		"number": toFloat64,
-		"times":  times,
-		"plus":   plus,
-		"minus":  minus,
-		"round":  round,
-		"isodd":  isodd,
-		"iseven": iseven,
-		"fromto": fromTo,
-		"upto":   upTo,
-		"grid":   gridTo,
-		"var":    newVar,
-		"set":    setVar,
-		"list":   list,
+		"float":   toFloat64,
+		"number":  toFloat64,
+		"times":   times,
+		"plus":    plus,
+		"minus":   minus,
+		"div":     div,
+		"mod":     mod,
+		"pow":     pow,
+		"sqrt":    sqrt,
+		"abs":     abs,
+		"min":     min,
+		"max":     max,
+		"clamp":   clamp,
+		"lerp":    lerp,
+		"pi":      pi,
+		"sin":     sin,
+		"cos":     cos,
+		"atan2":   atan2,
+		"polygon": polygon,
+		"star":    star,
+		"round":   round,
+		"isodd":   isodd,
+		"iseven":  iseven,
+		"fromto":  fromTo,
+		"upto":    upTo,
+		"grid":    gridTo,
+		"wrap":    wrap,
+		"var":     newVar,
+		"set":     setVar,
+		"list":    list,
 	}
 }
 
@@ -76,6 +93,156 @@ func minus(a, b interface{}) float64 {
 	return toFloat64(b) - toFloat64(a)
 }
 
+// div divides b by a (the parameter order is for pipelines like {{ $x | div 2 }}).
+// The division by zero gives zero.
+func div(a, b interface{}) float64 {
+	d := toFloat64(a)
+	if d == 0 {
+		return 0
+	}
+
+	return toFloat64(b) / d
+}
+
+// mod provides the remainder of b divided by a, with the sign of b.
+// The remainder modulo zero is zero.
+func mod(a, b interface{}) float64 {
+	d := toFloat64(a)
+	if d == 0 {
+		return 0
+	}
+
+	return math.Mod(toFloat64(b), d)
+}
+
+// pow raises b to the power a (the parameter order is for pipelines like {{ $x | pow 2 }}).
+func pow(a, b interface{}) float64 {
+	return math.Pow(toFloat64(b), toFloat64(a))
+}
+
+// sqrt provides the square root of x, or zero if x is negative.
+func sqrt(x interface{}) float64 {
+	return math.Sqrt(math.Max(toFloat64(x), 0))
+}
+
+// abs provides the absolute value of x.
+func abs(x interface{}) float64 {
+	return math.Abs(toFloat64(x))
+}
+
+// min provides the smallest of the parameters (zero if none).
+func min(values ...interface{}) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	m := toFloat64(values[0])
+	for _, v := range values[1:] {
+		m = math.Min(m, toFloat64(v))
+	}
+
+	return m
+}
+
+// max provides the largest of the parameters (zero if none).
+func max(values ...interface{}) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	m := toFloat64(values[0])
+	for _, v := range values[1:] {
+		m = math.Max(m, toFloat64(v))
+	}
+
+	return m
+}
+
+// clamp restricts x to the interval [lo, hi].
+func clamp(lo, hi, x interface{}) float64 {
+	return math.Min(math.Max(toFloat64(x), toFloat64(lo)), toFloat64(hi))
+}
+
+// lerp provides the linear interpolation a + t(b - a) between a and b.
+func lerp(a, b, t interface{}) float64 {
+	fa := toFloat64(a)
+
+	return fa + toFloat64(t)*(toFloat64(b)-fa)
+}
+
+// pi provides π.
+func pi() float64 {
+	return math.Pi
+}
+
+// sin provides the sine of the angle x in radians.
+func sin(x interface{}) float64 {
+	return math.Sin(toFloat64(x))
+}
+
+// cos provides the cosine of the angle x in radians.
+func cos(x interface{}) float64 {
+	return math.Cos(toFloat64(x))
+}
+
+// atan2 provides the angle in radians of the point (x,y).
+func atan2(y, x interface{}) float64 {
+	return math.Atan2(toFloat64(y), toFloat64(x))
+}
+
+// polygon provides the points "x1,y1 x2,y2 ..." of the regular polygon
+// with n vertices, centered at the origin, with circumradius r and
+// the first vertex at the top. The coordinates are rounded to 2 digits.
+// If n is less than 3, an empty string is provided.
+func polygon(n, r interface{}) string {
+	num, radius := int(toFloat64(n)), toFloat64(r)
+	if num < 3 {
+		return ""
+	}
+	points := make([]string, num)
+	for i := range points {
+		a := 2*math.Pi*float64(i)/float64(num) - math.Pi/2
+		points[i] = point(radius*math.Cos(a), radius*math.Sin(a))
+	}
+
+	return strings.Join(points, " ")
+}
+
+// star provides the points "x1,y1 x2,y2 ..." of the star with n points,
+// centered at the origin, with the first point at the top.
+// The 2n vertices are alternately at distance r1 (the points) and r2 (the notches)
+// from the center. The coordinates are rounded to 2 digits.
+// For the regular star polygon {n/k} the notches are at distance r2 = r1·cos(kπ/n)/cos((k-1)π/n).
+// If n is less than 2, an empty string is provided.
+func star(n, r1, r2 interface{}) string {
+	num, outer, inner := int(toFloat64(n)), toFloat64(r1), toFloat64(r2)
+	if num < 2 {
+		return ""
+	}
+	points := make([]string, 2*num)
+	for i := range points {
+		a := math.Pi*float64(i)/float64(num) - math.Pi/2
+		r := outer
+		if i%2 == 1 {
+			r = inner
+		}
+		points[i] = point(r*math.Cos(a), r*math.Sin(a))
+	}
+
+	return strings.Join(points, " ")
+}
+
+// point prints the point "x,y" with coordinates rounded to 2 digits.
+// The tiny negative coordinates are printed as 0 and not as -0.
+func point(x, y float64) string {
+	if math.Abs(x) < 0.005 {
+		x = 0
+	}
+	if math.Abs(y) < 0.005 {
+		y = 0
+	}
+
+	return round(2, x) + "," + round(2, y)
+}
+
 // round prints the f parameter with the provided precision.
 // The non significant digits (0) are removed.
 func round(precision interface{}, f interface{}) string {
@@ -158,6 +325,51 @@ func gridTo(num interface{}) (r [][]int) {
 	return r
 }
 
+// An Offset is a copy of a pattern element provided by wrap:
+// (X,Y) is the position of the copy, and (DX,DY) is its translation
+// from the original position.
+type Offset struct {
+	X, Y   float64
+	DX, DY float64
+}
+
+// wrap provides the copies of an element, at position (x,y) and
+// extending at most r around it, needed to tile a width×height pattern seamlessly.
+// The position is first moved inside the pattern (modulo width and height),
+// then the element is copied at ±width and/or ±height if it crosses the pattern bounds.
+// The first offset is the element itself.
+// Usage : {{ range wrap $w $h $r $x $y }}<circle cx="{{ .X }}" cy="{{ .Y }}" r="{{ $r }}"/>{{ end }}
+func wrap(width, height, r, x, y interface{}) []Offset {
+	w, h, e := toFloat64(width), toFloat64(height), math.Abs(toFloat64(r))
+	copies := func(v, size float64) []float64 {
+		if size <= 0 {
+			return []float64{v}
+		}
+		v = math.Mod(v, size)
+		if v < 0 {
+			v += size
+		}
+		c := []float64{v}
+		if v-e < 0 {
+			c = append(c, v+size)
+		}
+		if v+e > size {
+			c = append(c, v-size)
+		}
+		return c
+	}
+
+	x0, y0 := toFloat64(x), toFloat64(y)
+	var offsets []Offset
+	for _, cy := range copies(y0, h) {
+		for _, cx := range copies(x0, w) {
+			offsets = append(offsets, Offset{cx, cy, cx - x0, cy - y0})
+		}
+	}
+
+	return offsets
+}
+
 // newVar defines a new variable that can be modified
 // in a sub scope and the modification will be preserved.
 // Usage : {{ $a := var }}, then {{ 3 | set $a }}
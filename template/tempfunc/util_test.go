@@ -1,8 +1,12 @@
 package tempfunc
 
 import (
+	"bytes"
 	"fmt"
+	"math"
+	"math/rand"
 	"os"
+	"regexp"
 	"testing"
 	"text/template"
 )
@@ -141,3 +145,218 @@ func ExampleUtilFunctions_list() {
 	// Output:
 	// Hello, 2, 4, 8 !
 }
+
+func TestMathFunctions(t *testing.T) {
+	data := []struct {
+		res  float64
+		want float64
+		msg  string
+	}{
+		{div(2, 7), 3.5, "div 2 7 should be 7/2"},
+		{div(0, 7), 0, "division by zero should be zero"},
+		{mod(3, 7), 1, "mod 3 7 should be 7 mod 3"},
+		{mod(3, -7), -1, "mod should have the sign of the dividend"},
+		{mod("0", 7), 0, "modulo zero should be zero"},
+		{pow(2, "3"), 9, "pow 2 3 should be 3^2"},
+		{sqrt(16), 4, "sqrt 16 should be 4"},
+		{sqrt(-16), 0, "sqrt of negative should be zero"},
+		{abs(-1.5), 1.5, "abs -1.5 should be 1.5"},
+		{min(3, "1", 2.5), 1, "min should be the smallest value"},
+		{max(3, "1", 2.5), 3, "max should be the largest value"},
+		{min(), 0, "min of nothing should be zero"},
+		{clamp(0, 1, 1.7), 1, "clamp above"},
+		{clamp(0, 1, -0.3), 0, "clamp below"},
+		{clamp(0, 1, 0.3), 0.3, "clamp inside"},
+		{lerp(10, 20, 0.25), 12.5, "lerp 10 20 0.25 should be 12.5"},
+		{pi(), math.Pi, "pi"},
+		{sin(math.Pi / 2), 1, "sin pi/2 should be 1"},
+		{cos(0), 1, "cos 0 should be 1"},
+		{atan2(1, 1), math.Pi / 4, "atan2 1 1 should be pi/4"},
+	}
+	for _, tt := range data {
+		if math.Abs(tt.res-tt.want) > 1e-12 {
+			t.Errorf(tt.msg+", got %v, want %v", tt.res, tt.want)
+		}
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	data := []struct {
+		n, r interface{}
+		out  string
+	}{
+		{4, 10, "0,-10 10,0 0,10 -10,0"},
+		{"6", 10, "0,-10 8.66,-5 8.66,5 0,10 -8.66,5 -8.66,-5"},
+		{8, 1, "0,-1 0.71,-0.71 1,0 0.71,0.71 0,1 -0.71,0.71 -1,0 -0.71,-0.71"},
+		{2, 10, ""},
+	}
+	for _, tt := range data {
+		res := polygon(tt.n, tt.r)
+		if res != tt.out {
+			t.Errorf("polygon %v %v got %s, want %s", tt.n, tt.r, res, tt.out)
+		}
+	}
+}
+
+func TestStar(t *testing.T) {
+	data := []struct {
+		n, r1, r2 interface{}
+		out       string
+	}{
+		{4, 10, 5, "0,-10 3.54,-3.54 10,0 3.54,3.54 0,10 -3.54,3.54 -10,0 -3.54,-3.54"},
+		{"3", 2, 1, "0,-2 0.87,-0.5 1.73,1 0,1 -1.73,1 -0.87,-0.5"},
+		{1, 10, 5, ""},
+	}
+	for _, tt := range data {
+		res := star(tt.n, tt.r1, tt.r2)
+		if res != tt.out {
+			t.Errorf("star %v %v %v got %s, want %s", tt.n, tt.r1, tt.r2, res, tt.out)
+		}
+	}
+}
+
+// The functions `div`, `mod` and `pow` take the second parameter
+// as the value to operate on, in the same way as `minus`.
+func ExampleUtilFunctions_div() {
+	const hello string = `Hello, {{ 7 | div 2 }}, {{ 7 | mod 2 }} and {{ 3 | pow 2 }} !`
+	// compile and execute the template (without error check, very bad idea!)
+	t, _ := template.New("hi").Funcs(UtilFunctions()).Parse(hello)
+	t.Execute(os.Stdout, nil)
+	// Output:
+	// Hello, 3.5, 1 and 9 !
+}
+
+// The trigonometric functions use radians.
+func ExampleUtilFunctions_trigonometry() {
+	const hello string = `Hello, {{ pi | div 6 | sin | round 2 }} and {{ atan2 1 0 | div pi | round 2 }} !`
+	// compile and execute the template (without error check, very bad idea!)
+	t, _ := template.New("hi").Funcs(UtilFunctions()).Parse(hello)
+	t.Execute(os.Stdout, nil)
+	// Output:
+	// Hello, 0.5 and 0.5 !
+}
+
+// The function `polygon` provides the points of a regular polygon.
+func ExampleUtilFunctions_polygon() {
+	const hello string = `<polygon points="{{ polygon 3 10 }}"/>`
+	// compile and execute the template (without error check, very bad idea!)
+	t, _ := template.New("hi").Funcs(UtilFunctions()).Parse(hello)
+	t.Execute(os.Stdout, nil)
+	// Output:
+	// <polygon points="0,-10 8.66,5 -8.66,5"/>
+}
+
+func TestWrap(t *testing.T) {
+	data := []struct {
+		x, y interface{}
+		out  string
+	}{
+		{50, 50, "[{50 50 0 0}]"},
+		{5, 50, "[{5 50 0 0} {105 50 100 0}]"},
+		{50, 95, "[{50 95 0 0} {50 -5 0 -100}]"},
+		{-5, 5, "[{95 5 100 0} {-5 5 0 0} {95 105 100 100} {-5 105 0 100}]"},
+		{"250", 50, "[{50 50 -200 0}]"},
+	}
+	for _, tt := range data {
+		res := fmt.Sprintf("%v", wrap(100, 100, 10, tt.x, tt.y))
+		if res != tt.out {
+			t.Errorf("wrap 100 100 10 %v %v got %s, want %s", tt.x, tt.y, res, tt.out)
+		}
+	}
+}
+
+// A circle is a disk of a pattern tile.
+type circle struct {
+	X, Y, R float64
+}
+
+// circles reads the <circle cx="x" cy="y" r="r"/> elements of the svg code.
+func circles(svg string) (c []circle) {
+	re := regexp.MustCompile(`<circle cx="([^"]*)" cy="([^"]*)" r="([^"]*)"/>`)
+	for _, m := range re.FindAllStringSubmatch(svg, -1) {
+		c = append(c, circle{toFloat64(m[1]), toFloat64(m[2]), toFloat64(m[3])})
+	}
+
+	return c
+}
+
+// seamErrors rasterizes the border pixels (at distance less than r) of the
+// width×height tile drawn with the tile circles, and compares them with the
+// pixels of the infinite periodic pattern of the original circles.
+// It provides the number of different pixels, that are zero if the tile is seamless.
+func seamErrors(tile, original []circle, width, height, r int) (errors int) {
+	covered := func(x, y float64, cs []circle, periodic bool) bool {
+		for _, c := range cs {
+			dx, dy := math.Abs(x-c.X), math.Abs(y-c.Y)
+			if periodic {
+				dx = math.Min(math.Mod(dx, float64(width)), float64(width)-math.Mod(dx, float64(width)))
+				dy = math.Min(math.Mod(dy, float64(height)), float64(height)-math.Mod(dy, float64(height)))
+			}
+			if dx*dx+dy*dy <= c.R*c.R {
+				return true
+			}
+		}
+		return false
+	}
+	for i := 0; i < width; i++ {
+		for j := 0; j < height; j++ {
+			if i >= r && i < width-r && j >= r && j < height-r {
+				continue
+			}
+			x, y := float64(i)+0.5, float64(j)+0.5
+			if covered(x, y, tile, false) != covered(x, y, original, true) {
+				errors++
+			}
+		}
+	}
+
+	return errors
+}
+
+func TestWrapSeams(t *testing.T) {
+	const code = `{{ range $c := . }}{{ range wrap 100 80 $c.R $c.X $c.Y }}<circle cx="{{ .X }}" cy="{{ .Y }}" r="{{ $c.R }}"/>{{ end }}{{ end }}`
+	tmpl := template.Must(template.New("wrap").Funcs(UtilFunctions()).Parse(code))
+
+	// random circles, many of them crossing the tile bounds
+	r := rand.New(rand.NewSource(42))
+	original := make([]circle, 30)
+	for i := range original {
+		original[i] = circle{r.Float64()*120 - 10, r.Float64()*100 - 10, 3 + r.Float64()*12}
+	}
+	var svg bytes.Buffer
+	if err := tmpl.Execute(&svg, original); err != nil {
+		t.Fatal(err)
+	}
+
+	tile := circles(svg.String())
+	if len(tile) <= len(original) {
+		t.Fatalf("some circles should be copied, got %d circles from %d", len(tile), len(original))
+	}
+	if n := seamErrors(tile, original, 100, 80, 16); n > 0 {
+		t.Errorf("the wrapped circles should be seamless, got %d different border pixels", n)
+	}
+	// the harness detects the seams of the not wrapped circles
+	if n := seamErrors(original, original, 100, 80, 16); n == 0 {
+		t.Error("the not wrapped circles should not be seamless")
+	}
+}
+
+// The function `wrap` provides the copies of an element that crosses the pattern bounds.
+func ExampleUtilFunctions_wrap() {
+	const hello string = `{{ range wrap 100 100 10 95 50 }}<circle cx="{{ .X }}" cy="{{ .Y }}" r="10"/>{{ end }}`
+	// compile and execute the template (without error check, very bad idea!)
+	t, _ := template.New("hi").Funcs(UtilFunctions()).Parse(hello)
+	t.Execute(os.Stdout, nil)
+	// Output:
+	// <circle cx="95" cy="50" r="10"/><circle cx="-5" cy="50" r="10"/>
+}
+
+// The function `star` provides the points of a star with alternating radii.
+func ExampleUtilFunctions_star() {
+	const hello string = `<polygon points="{{ star 4 10 2 }}"/>`
+	// compile and execute the template (without error check, very bad idea!)
+	t, _ := template.New("hi").Funcs(UtilFunctions()).Parse(hello)
+	t.Execute(os.Stdout, nil)
+	// Output:
+	// <polygon points="0,-10 1.41,-1.41 10,0 1.41,1.41 0,10 -1.41,1.41 -10,0 -1.41,-1.41"/>
+}
@@ -0,0 +1,85 @@
+package svgpattern
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/kpym/svgpattern/template/tempfunc"
+)
+
+// A text is written on top of the pattern.
+type text struct {
+	content string
+	font    string
+	size    float64
+	anchor  string
+}
+
+// textAnchors provides the position (x, y in %), the text-anchor
+// and the dominant-baseline of each available anchor.
+var textAnchors = map[string]struct {
+	x, y            int
+	align, baseline string
+}{
+	"center":       {50, 50, "middle", "central"},
+	"top":          {50, 5, "middle", "hanging"},
+	"bottom":       {50, 95, "middle", "text-after-edge"},
+	"left":         {5, 50, "start", "central"},
+	"right":        {95, 50, "end", "central"},
+	"top-left":     {5, 5, "start", "hanging"},
+	"top-right":    {95, 5, "end", "hanging"},
+	"bottom-left":  {5, 95, "start", "text-after-edge"},
+	"bottom-right": {95, 95, "end", "text-after-edge"},
+}
+
+// textTemplate is the svg text element.
+var textTemplate = template.Must(template.New("text").Parse(`  <text x="{{ .X }}%" y="{{ .Y }}%" fill="{{ .Fill }}" font-family="{{ html .Font }}" font-size="{{ .Size }}" text-anchor="{{ .Align }}" dominant-baseline="{{ .Baseline }}">{{ html .Content }}</text>
+`))
+
+// addText inserts the text element before the closing tag of the svg.
+func (g *generator) addText(svg []byte) ([]byte, error) {
+	var result bytes.Buffer
+
+	end := bytes.LastIndex(svg, []byte("</svg>"))
+	if end < 0 {
+		return nil, fmt.Errorf("missing </svg> closing tag")
+	}
+	a := textAnchors[g.text.anchor]
+	data := struct {
+		X, Y                                 int
+		Fill, Font, Align, Baseline, Content string
+		Size                                 float64
+	}{
+		a.x, a.y,
+		tempfunc.Contrast(g.color), g.text.font, a.align, a.baseline, g.text.content,
+		g.text.size,
+	}
+
+	result.Write(bytes.TrimRight(svg[:end], " \t\n"))
+	result.WriteString("\n")
+	err := textTemplate.Execute(&result, data)
+	result.Write(svg[end:])
+
+	return result.Bytes(), err
+}
+
+// WithText is a Generator option that writes a text on top of the pattern.
+// The text color (black or white) is chosen for contrast against the background color.
+// The font family and the size (in pixels) are used as provided.
+// The anchor is the position of the text: 'center', 'top', 'bottom', 'left', 'right',
+// 'top-left', 'top-right', 'bottom-left' or 'bottom-right'.
+// If the anchor is unknown 'center' is used.
+func WithText(content, font string, size float64, anchor string) Option {
+	return func(g *generator) {
+		if _, ok := textAnchors[anchor]; !ok {
+			g.addError(fmt.Sprintf("Unknown text anchor %s, use center.", anchor))
+			anchor = "center"
+		}
+		if size <= 0 {
+			g.addError(fmt.Sprintf("Invalid text size %v.", size))
+			return
+		}
+		g.text = &text{content, font, size, anchor}
+	}
+}
@@ -0,0 +1,32 @@
+package svgpattern
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithText(t *testing.T) {
+	g := New("Test", WithModel("chevrons"), WithColor("#fff"), WithText("Hi & bye", "serif", 32, "top-right"))
+	svg, ok := g.Generate()
+	if !ok {
+		t.Error("There are errors in the generator.", g.Errors())
+	}
+	want := `  <rect fill="url(#pattern)" height="100%" width="100%" x="0" y="0"/>
+  <text x="95%" y="5%" fill="#000000" font-family="serif" font-size="32" text-anchor="end" dominant-baseline="hanging">Hi &amp; bye</text>
+</svg>`
+	if !strings.Contains(string(svg), want) {
+		t.Errorf("The text should be added after the pattern, got: %s", svg)
+	}
+
+	g = New("Test", WithText("Hi", "serif", 32, "bingo"))
+	svg, _ = g.Generate()
+	if len(g.Errors()) != 1 || !strings.Contains(string(svg), `x="50%" y="50%"`) {
+		t.Errorf("An unknown anchor should produce an error and use center, got errors %v.", g.Errors())
+	}
+
+	g = New("Test", WithText("Hi", "serif", 0, "center"))
+	svg, _ = g.Generate()
+	if len(g.Errors()) != 1 || strings.Contains(string(svg), "<text") {
+		t.Errorf("An invalid size should produce an error and no text, got errors %v.", g.Errors())
+	}
+}
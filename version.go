@@ -0,0 +1,110 @@
+package svgpattern
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/kpym/svgpattern/template/model"
+)
+
+// An outputVersion is the frozen seeding logic of a release.
+// The models of the version are in model.Versions.
+type outputVersion struct {
+	// phraseSeed converts a (non empty) phrase to seed.
+	phraseSeed func(phrase string) int64
+	// streamSeed derives the seed of the random stream named label.
+	streamSeed func(seed int64, label string) int64
+	// shared is true if all the parameters are drawn, in order,
+	// from a single random stream seeded by the phrase seed.
+	shared bool
+	// namespaced is true if the models prefix their ids with $.ID
+	// and draw with the palette $.Dark and $.Light.
+	namespaced bool
+}
+
+// outputVersions contains the seeding logic of all output versions.
+// Once released a version should never be modified.
+var outputVersions = map[string]outputVersion{
+	"v1": {phraseSeedV1, streamSeedV1, true, false},
+	// v2 uses all the phrase hash and draws each parameter from its own random stream
+	"v2": {phraseSeedV2, streamSeedV2, false, true},
+}
+
+// phraseSeedV1 use the sha1 sum of the provided phrase as seed.
+func phraseSeedV1(phrase string) int64 {
+	h := sha1.New()
+	h.Write([]byte(phrase))
+	hs := h.Sum(nil)
+	// the first byte is repeated 8 times, so there are only 256 possible seeds,
+	// this is kept as released (see phraseSeedV2)
+	var seed int64
+	for i := 0; i < 8; i++ {
+		seed += int64(hs[0]) << (i * 8)
+	}
+
+	return seed
+}
+
+// phraseSeedV2 use the first 8 bytes of the sha1 sum of the provided phrase as seed.
+// This gives 2^64 possible seeds.
+func phraseSeedV2(phrase string) int64 {
+	h := sha1.New()
+	h.Write([]byte(phrase))
+	hs := h.Sum(nil)
+
+	return int64(binary.LittleEndian.Uint64(hs[:8]))
+}
+
+// streamSeedV1 provides the seed itself for the template, as released,
+// and derives the seeds of the other templates (the composition layers) like v2.
+func streamSeedV1(seed int64, label string) int64 {
+	if label == "template" {
+		return seed
+	}
+
+	return streamSeedV2(seed, label)
+}
+
+// streamSeedV2 derives the seed of the label stream
+// from the sha1 sum of the seed and the label.
+func streamSeedV2(seed int64, label string) int64 {
+	h := sha1.New()
+	binary.Write(h, binary.LittleEndian, seed)
+	h.Write([]byte(label))
+	hs := h.Sum(nil)
+
+	return int64(binary.LittleEndian.Uint64(hs))
+}
+
+// WithOutputVersion is a Generator option that select the output version, like "v1".
+// The patterns generated with a given version never change (for the same options),
+// even if the models or the seeding logic are modified in a later release.
+// By default the latest version is used (see model.LatestVersion).
+// This option should be the first one, as it resets the color and the model,
+// otherwise an error is reported.
+func WithOutputVersion(version string) Option {
+	return func(g *generator) {
+		ov, ok := outputVersions[version]
+		models, okm := model.Versions[version]
+		if !ok || !okm {
+			g.addError(fmt.Sprintf("Unknown output version %s, use %s.", version, g.version))
+			return
+		}
+		if g.applied > 0 {
+			g.addError(fmt.Sprintf("The output version %s should be the first option, the previous color and model are discarded.", version))
+		}
+
+		g.version = version
+		g.output = ov
+		g.models = models
+		g.weights = nil
+		if g.phrase != "" {
+			g.phraseSeed(g.phrase)
+		} else {
+			g.setSeed(g.seed)
+		}
+		g.randomColor()
+		g.randomModel()
+	}
+}